@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import "errors"
+
+/*
+
+OrElse evaluates alternative arrows if the head fails.
+(a ⟼ b) ∨ (a ⟼ c)
+*/
+func (head Arrow) OrElse(arrows ...Arrow) Arrow {
+	return Alt(head, Join(arrows...))
+}
+
+/*
+
+Alt evaluates arrows one by one until first one succeeds. The failure of
+category is recovered and HTTP state is reset before evaluation of next
+alternative. The evaluation fails with the failure of last arrow if none of
+them succeeds.
+*/
+func Alt(arrows ...Arrow) Arrow {
+	return func(cat *IOCat) *IOCat {
+		for i, f := range arrows {
+			if cat.Done() {
+				return cat
+			}
+
+			if i > 0 {
+				cat.Reset()
+				cat.Fail = nil
+			}
+
+			if cat = f(cat); cat.Fail == nil {
+				return cat
+			}
+		}
+		return cat
+	}
+}
+
+/*
+
+Case is a branch of pattern-matching over failures. It returns arrow to
+evaluate if the failure matches the pattern, nil otherwise.
+*/
+type Case func(error) Arrow
+
+/*
+
+Match evaluates the first case, which matches the failure of head. The failure
+of category is recovered and HTTP state is reset before evaluation of the case.
+The failure is preserved if none of cases matches it.
+  http.Join(
+    ø.GET("https://example.com/user/%s", id),
+    ƒ.Code(http.StatusOK),
+  ).Then(
+    update,
+  ).Match(
+    http.OnStatus(http.StatusNotFound, create),
+    assay.OnMismatch(...),
+  )
+*/
+func (head Arrow) Match(cases ...Case) Arrow {
+	return func(cat *IOCat) *IOCat {
+		if cat = head(cat); cat.Fail == nil || cat.Done() {
+			return cat
+		}
+
+		for _, c := range cases {
+			if f := c(cat.Fail); f != nil {
+				cat.Reset()
+				cat.Fail = nil
+				return f(cat)
+			}
+		}
+		return cat
+	}
+}
+
+/*
+
+On matches failures using predicate.
+*/
+func On(pred func(error) bool, arrows ...Arrow) Case {
+	f := Join(arrows...)
+	return func(err error) Arrow {
+		if pred(err) {
+			return f
+		}
+		return nil
+	}
+}
+
+// OnMismatch matches Mismatch failures
+func OnMismatch(arrows ...Arrow) Case {
+	return On(func(err error) bool {
+		var e *Mismatch
+		return errors.As(err, &e)
+	}, arrows...)
+}
+
+// OnNotSupported matches NotSupported failures
+func OnNotSupported(arrows ...Arrow) Case {
+	return On(func(err error) bool {
+		var e *NotSupported
+		return errors.As(err, &e)
+	}, arrows...)
+}
+
+// OnUndefined matches Undefined failures
+func OnUndefined(arrows ...Arrow) Case {
+	return On(func(err error) bool {
+		var e *Undefined
+		return errors.As(err, &e)
+	}, arrows...)
+}
+
+// OnAny matches any failure
+func OnAny(arrows ...Arrow) Case {
+	return On(func(error) bool { return true }, arrows...)
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+func failWith(err error) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		cat.Fail = err
+		return cat
+	}
+}
+
+func mark(s *string, val string) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		*s = val
+		return cat
+	}
+}
+
+func TestOrElse(t *testing.T) {
+	var s string
+	for _, f := range []assay.Arrow{
+		fail().OrElse(mark(&s, "else")),
+		assay.Alt(fail(), fail(), mark(&s, "else")),
+	} {
+		s = ""
+		if c := f(assay.IO()); c.Fail != nil || s != "else" {
+			t.Error("alternative is not evaluated")
+		}
+	}
+}
+
+func TestOrElseSkip(t *testing.T) {
+	var s string
+	f := identity().OrElse(mark(&s, "else"))
+
+	if c := f(assay.IO()); c.Fail != nil || s != "" {
+		t.Error("alternative is evaluated")
+	}
+}
+
+func TestAltFail(t *testing.T) {
+	f := assay.Alt(fail(), failWith(&assay.Undefined{Type: "alt"}))
+
+	var e *assay.Undefined
+	if c := f(assay.IO()); !errors.As(c.Fail, &e) {
+		t.Error("alternative do not report last failure")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	var s string
+	for expect, err := range map[string]error{
+		"mismatch":      &assay.Mismatch{Diff: "-"},
+		"undefined":     &assay.Undefined{Type: "match"},
+		"not supported": &assay.NotSupported{},
+		"any":           errors.New("fail"),
+	} {
+		f := failWith(err).Match(
+			assay.OnMismatch(mark(&s, "mismatch")),
+			assay.OnUndefined(mark(&s, "undefined")),
+			assay.OnNotSupported(mark(&s, "not supported")),
+			assay.OnAny(mark(&s, "any")),
+		)
+
+		if c := f(assay.IO()); c.Fail != nil || s != expect {
+			t.Errorf("unexpected branch %s, required %s", s, expect)
+		}
+	}
+}
+
+func TestMatchNone(t *testing.T) {
+	var s string
+	f := fail().Match(assay.OnMismatch(mark(&s, "mismatch")))
+
+	if c := f(assay.IO()); c.Fail == nil || s != "" {
+		t.Error("unmatched failure is recovered")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 /*
@@ -32,13 +33,36 @@ type UpStreamHTTP struct {
 	URL     *url.URL
 	Header  map[string]*string
 	Payload io.Reader
+	Timeout time.Duration
 }
 
 /*
 
-DnStreamHTTP specify parameters for HTTP response
+DnStreamHTTP specify parameters for HTTP response. The status code of response
+is also available as Code, after the response is consumed.
 */
 type DnStreamHTTP struct {
 	*http.Response
+	Code    int
 	Payload interface{}
+	Timing  Timing
+}
+
+/*
+
+Timing of HTTP request
+  - DNS lookup
+  - Connect is time to establish TCP connection
+  - TLS handshake
+  - TTFB is time to first byte of response since request is written
+  - Total is time since beginning of request until response headers are received
+
+The phases DNS, Connect and TLS are zero if connection is reused.
+*/
+type Timing struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	TTFB    time.Duration
+	Total   time.Duration
 }
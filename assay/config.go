@@ -8,6 +8,8 @@
 
 package assay
 
+import "context"
+
 /*
 
 Log Level constants, use with Logging config
@@ -37,6 +39,80 @@ func Logging(level int) Config {
 
 /*
 
+LogWith installs the logger of I/O traffic, the config can be used multiple
+times to install several loggers. It enables logging of egress and ingress
+traffic unless level is defined by Logging config.
+*/
+func LogWith(logger Logger) Config {
+	return func(cat *IOCat) *IOCat {
+		cat.loggers = append(cat.loggers, logger)
+		if cat.LogLevel == LogLevelNone {
+			cat.LogLevel = LogLevelIngress
+		}
+		return cat
+	}
+}
+
+/*
+
+Tracing enables step-level tracing of arrow composition. Join and Then
+record each step into the category, use Trace to inspect them.
+*/
+func Tracing() Config {
+	return func(cat *IOCat) *IOCat {
+		cat.trace.enabled = true
+		return cat
+	}
+}
+
+/*
+
+Observe installs the observer of I/O traffic. Unlike loggers, observers
+receive all events, including content of packets, regardless of the log
+level and they do not replace the default logging.
+*/
+func Observe(observer Logger) Config {
+	return ObserveAt(LogLevelDebug, observer)
+}
+
+/*
+
+ObserveAt installs the observer of I/O traffic, which requires details up to
+the level, e.g. LogLevelIngress observes requests and responses without
+content of packets. Side effects capture content only if it is required.
+*/
+func ObserveAt(level int, observer Logger) Config {
+	return func(cat *IOCat) *IOCat {
+		cat.observers = append(cat.observers, observe{Logger: observer, level: level})
+		return cat
+	}
+}
+
+/*
+
+Redact defines rules to hide sensitive data of I/O traffic from logs and
+payloads of Mismatch failures. The rules extends DefaultRedactions.
+*/
+func Redact(rules ...Redaction) Config {
+	return func(cat *IOCat) *IOCat {
+		cat.redactions = append(cat.rules(), rules...)
+		return cat
+	}
+}
+
+/*
+
+NoRedaction disables any redaction rules, including the default one.
+*/
+func NoRedaction() Config {
+	return func(cat *IOCat) *IOCat {
+		cat.redactions = []Redaction{}
+		return cat
+	}
+}
+
+/*
+
 SideEffect defines "unsafe" behavior for category
 */
 func SideEffect(arrow Arrow) Config {
@@ -45,3 +121,36 @@ func SideEffect(arrow Arrow) Config {
 		return cat
 	}
 }
+
+/*
+
+Interceptor wraps the side effect of category, e.g. to observe or to alter
+I/O of each exchange
+*/
+type Interceptor func(Arrow) Arrow
+
+/*
+
+Intercept installs the interceptor of side effect. Interceptors are applied
+regardless of the order of configs, the first installed interceptor is the
+outermost one.
+*/
+func Intercept(interceptor Interceptor) Config {
+	return func(cat *IOCat) *IOCat {
+		cat.interceptors = append(cat.interceptors, interceptor)
+		return cat
+	}
+}
+
+/*
+
+Context binds the category with the context. The context is propagated
+through arrows and side effects, the evaluation stops with Canceled error
+once the context is done.
+*/
+func Context(ctx context.Context) Config {
+	return func(cat *IOCat) *IOCat {
+		cat.ctx = ctx
+		return cat
+	}
+}
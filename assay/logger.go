@@ -0,0 +1,189 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+
+Event types, the event is either egress or ingress traffic
+*/
+const (
+	//
+	EventEgress  = "egress"
+	EventIngress = "ingress"
+)
+
+/*
+
+Event is a structured record of I/O traffic emitted by side effects. Egress
+events define the request (Method, URL, Header, Body). Ingress events
+define the response (Status, Duration, Header, Body) along with Method and
+URL of the request. Body is captured only with LogLevelDebug.
+*/
+type Event struct {
+	Type     string
+	Method   string
+	URL      *url.URL
+	Proto    string
+	Status   int
+	Duration time.Duration
+	Size     int64
+	Header   http.Header
+	Body     []byte
+}
+
+// String formats the event as HTTP message
+func (e *Event) String() string {
+	var msg strings.Builder
+
+	switch e.Type {
+	case EventEgress:
+		fmt.Fprintf(&msg, "%s %s %s\r\nHost: %s\r\n", e.Method, e.URL.RequestURI(), e.Proto, e.URL.Host)
+	case EventIngress:
+		fmt.Fprintf(&msg, "%s %d %s\r\n", e.Proto, e.Status, http.StatusText(e.Status))
+	}
+	e.Header.Write(&msg)
+	msg.WriteString("\r\n")
+	msg.Write(e.Body)
+
+	return msg.String()
+}
+
+/*
+
+Logger consumes events of I/O traffic
+*/
+type Logger interface {
+	Log(*Event)
+}
+
+// LoggerFunc is an adapter to use ordinary functions as Logger
+type LoggerFunc func(*Event)
+
+// Log calls f(e)
+func (f LoggerFunc) Log(e *Event) { f(e) }
+
+/*
+
+TB is a subset of testing.TB interface required by TestLogger
+*/
+type TB interface {
+	Helper()
+	Log(args ...interface{})
+}
+
+/*
+
+TestLogger captures events into log of the test
+  assay.IO(
+    http.Default(),
+    assay.LogWith(assay.TestLogger(t)),
+  )
+*/
+func TestLogger(t TB) Logger {
+	return LoggerFunc(func(e *Event) {
+		t.Helper()
+		t.Log(prefix(e) + "\n" + e.String())
+	})
+}
+
+// stdLogger dumps events using standard log package, it is used by default
+var stdLogger = LoggerFunc(func(e *Event) {
+	log.Printf("%s\n%s\n", prefix(e), e)
+})
+
+func prefix(e *Event) string {
+	if e.Type == EventIngress {
+		return fmt.Sprintf("<<<< %s %s (%v, %d bytes)", e.Method, e.URL, e.Duration, e.Size)
+	}
+	return fmt.Sprintf(">>>> %s %s (%d bytes)", e.Method, e.URL, e.Size)
+}
+
+/*
+
+Log emits the event to observers and loggers of category, the event is
+redacted using rules of category. The event is dumped with standard log
+package if the category is not configured with loggers. Details emitted
+only for observers are not passed to loggers.
+*/
+func (cat *IOCat) Log(e *Event) {
+	cat.redact(e)
+
+	for _, observer := range cat.observers {
+		if logs(observer.level, e) {
+			observer.Log(details(observer.level, e))
+		}
+	}
+
+	if cat.Verbosity() > cat.LogLevel {
+		if !logs(cat.LogLevel, e) {
+			return
+		}
+		e = details(cat.LogLevel, e)
+	}
+
+	if len(cat.loggers) == 0 {
+		stdLogger.Log(e)
+		return
+	}
+
+	for _, logger := range cat.loggers {
+		logger.Log(e)
+	}
+}
+
+// observe is the observer of I/O traffic with the level of required details
+type observe struct {
+	Logger
+	level int
+}
+
+func logs(level int, e *Event) bool {
+	switch e.Type {
+	case EventEgress:
+		return level >= LogLevelEgress
+	case EventIngress:
+		return level >= LogLevelIngress
+	default:
+		return level > LogLevelNone
+	}
+}
+
+// details hides content of packets from consumers below debug level
+func details(level int, e *Event) *Event {
+	if level < LogLevelDebug && len(e.Body) > 0 {
+		clone := *e
+		clone.Body = nil
+		return &clone
+	}
+	return e
+}
+
+/*
+
+Verbosity returns the level of details required by loggers and observers of
+category. Side effects use it to decide which events to emit.
+*/
+func (cat *IOCat) Verbosity() int {
+	level := cat.LogLevel
+	for _, observer := range cat.observers {
+		if observer.level > level {
+			level = observer.level
+		}
+	}
+	return level
+}
@@ -0,0 +1,134 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+func event() *assay.Event {
+	uri, _ := url.Parse("https://example.com/a?b=c")
+	return &assay.Event{
+		Type:   assay.EventEgress,
+		Method: "GET",
+		URL:    uri,
+		Proto:  "HTTP/1.1",
+		Header: http.Header{
+			"Authorization": {"Basic secret"},
+			"Accept":        {"application/json"},
+		},
+		Body: []byte(`{"a":"b"}`),
+	}
+}
+
+func TestLogWith(t *testing.T) {
+	seq := []*assay.Event{}
+	c := assay.IO(
+		assay.LogWith(assay.LoggerFunc(func(e *assay.Event) { seq = append(seq, e) })),
+	)
+
+	if c.LogLevel != assay.LogLevelIngress {
+		t.Error("logger do not enable logging")
+	}
+
+	c.Log(event())
+	if len(seq) != 1 || seq[0].Method != "GET" {
+		t.Error("logger do not receive events")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	var e *assay.Event
+	c := assay.IO(
+		assay.LogWith(assay.LoggerFunc(func(x *assay.Event) { e = x })),
+		assay.Redact(
+			assay.RedactHeader("authorization", "x-missing"),
+			assay.RedactBody(),
+		),
+	)
+
+	c.Log(event())
+	if e.Header.Get("Authorization") != assay.Redacted ||
+		e.Header.Get("Accept") != "application/json" ||
+		e.Header.Get("X-Missing") != "" ||
+		string(e.Body) != assay.Redacted {
+		t.Error("event is not redacted")
+	}
+}
+
+func TestEventString(t *testing.T) {
+	msg := event().String()
+
+	if !strings.HasPrefix(msg, "GET /a?b=c HTTP/1.1\r\nHost: example.com\r\n") ||
+		!strings.HasSuffix(msg, "\r\n\r\n{\"a\":\"b\"}") {
+		t.Errorf("unexpected format of event %s", msg)
+	}
+}
+
+type tb struct{ seq []string }
+
+func (t *tb) Helper() {}
+
+func (t *tb) Log(args ...interface{}) {
+	t.seq = append(t.seq, args[0].(string))
+}
+
+func TestTestLogger(t *testing.T) {
+	log := &tb{}
+	c := assay.IO(assay.LogWith(assay.TestLogger(log)))
+
+	c.Log(event())
+	if len(log.seq) != 1 || !strings.HasPrefix(log.seq[0], ">>>> GET https://example.com/a?b=c") {
+		t.Error("event is not logged to test")
+	}
+}
+
+func TestObserve(t *testing.T) {
+	var logged, observed *assay.Event
+	c := assay.IO(
+		assay.LogWith(assay.LoggerFunc(func(e *assay.Event) { logged = e })),
+		assay.Observe(assay.LoggerFunc(func(e *assay.Event) { observed = e })),
+	)
+
+	if c.Verbosity() != assay.LogLevelDebug {
+		t.Error("observer do not require details")
+	}
+
+	c.Log(event())
+	if observed == nil || len(observed.Body) == 0 {
+		t.Error("observer do not receive events")
+	}
+
+	if logged == nil || len(logged.Body) != 0 {
+		t.Error("logger receives content of packets")
+	}
+}
+
+func TestObserveAt(t *testing.T) {
+	var observed *assay.Event
+	c := assay.IO(
+		assay.ObserveAt(assay.LogLevelIngress, assay.LoggerFunc(func(e *assay.Event) { observed = e })),
+		assay.LogWith(assay.LoggerFunc(func(e *assay.Event) {})),
+		assay.Logging(assay.LogLevelNone),
+	)
+
+	if c.Verbosity() != assay.LogLevelIngress {
+		t.Error("observer requires content of packets")
+	}
+
+	c.Log(event())
+	if observed == nil || len(observed.Body) != 0 {
+		t.Error("observer receives content of packets")
+	}
+}
@@ -0,0 +1,303 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+
+Redaction is a rule to hide sensitive data of events before they are logged.
+*/
+type Redaction func(*Event)
+
+// Redacted is a value used to replace sensitive data
+const Redacted = "[REDACTED]"
+
+/*
+
+DefaultRedactions are rules applied to category unless it is configured
+with NoRedaction. The rules hide credentials headers (Authorization,
+Cookie), secrets and tokens at body and query params (client_secret,
+access_token, refresh_token, password) and credentials of HTTP
+authorization schemes (Basic, Bearer) given as value of Authorization or
+Proxy-Authorization header within body or diff text.
+*/
+func DefaultRedactions() []Redaction {
+	return []Redaction{
+		RedactHeader("Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"),
+		RedactField("**.client_secret", "**.access_token", "**.refresh_token", "**.password"),
+		RedactQuery("client_secret", "access_token"),
+		RedactRegexp(regexp.MustCompile(`(?i)\bauthorization"?\s*[:=]\s*"?(?:basic|bearer)\s+([A-Za-z0-9\-._~+/]+=*)`)),
+	}
+}
+
+/*
+
+RedactHeader hides values of headers.
+*/
+func RedactHeader(headers ...string) Redaction {
+	return func(e *Event) {
+		for _, header := range headers {
+			if _, has := e.Header[http.CanonicalHeaderKey(header)]; has {
+				e.Header.Set(header, Redacted)
+			}
+		}
+	}
+}
+
+/*
+
+RedactBody hides the content of events.
+*/
+func RedactBody() Redaction {
+	return func(e *Event) {
+		if len(e.Body) > 0 {
+			e.Body = []byte(Redacted)
+		}
+	}
+}
+
+/*
+
+RedactQuery hides values of URL query params.
+*/
+func RedactQuery(params ...string) Redaction {
+	return func(e *Event) {
+		if e.URL == nil || e.URL.RawQuery == "" {
+			return
+		}
+
+		query := e.URL.Query()
+		changed := false
+		for _, param := range params {
+			if _, has := query[param]; has {
+				query.Set(param, Redacted)
+				changed = true
+			}
+		}
+
+		if changed {
+			uri := *e.URL
+			uri.RawQuery = query.Encode()
+			e.URL = &uri
+		}
+	}
+}
+
+/*
+
+RedactField hides values of fields at JSON or form encoded body. The field
+is defined by path of keys, separated by dot, starting from root of the
+document. The wildcard "*" matches any key or index of array, "**" matches
+any depth of document.
+  assay.RedactField("user.password", "tokens.*.secret", "**.client_secret")
+
+Only top level keys (e.g. "password" or "**.password") are matched at form
+encoded body.
+*/
+func RedactField(paths ...string) Redaction {
+	fields := make([][]string, len(paths))
+	forms := make([]*regexp.Regexp, 0, len(paths))
+	for i, path := range paths {
+		fields[i] = strings.Split(path, ".")
+		if key := fields[i][len(fields[i])-1]; len(fields[i]) == 1 ||
+			(len(fields[i]) == 2 && fields[i][0] == "**") {
+			forms = append(forms,
+				regexp.MustCompile(`(^|[&\s])`+regexp.QuoteMeta(key)+`=([^&\s]*)`))
+		}
+	}
+
+	return func(e *Event) {
+		if len(e.Body) == 0 {
+			return
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(e.Body, &doc); err == nil {
+			changed := false
+			for _, path := range fields {
+				changed = redactPath(doc, path) || changed
+			}
+			if changed {
+				if body, err := json.Marshal(doc); err == nil {
+					e.Body = body
+				}
+			}
+			return
+		}
+
+		for _, form := range forms {
+			e.Body = form.ReplaceAllFunc(e.Body, func(match []byte) []byte {
+				at := bytes.IndexByte(match, '=')
+				return append(append([]byte{}, match[:at+1]...), Redacted...)
+			})
+		}
+	}
+}
+
+func redactPath(node interface{}, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	head, tail := path[0], path[1:]
+	if head == "**" {
+		changed := redactPath(node, tail)
+		switch v := node.(type) {
+		case map[string]interface{}:
+			for _, x := range v {
+				changed = redactPath(x, path) || changed
+			}
+		case []interface{}:
+			for _, x := range v {
+				changed = redactPath(x, path) || changed
+			}
+		}
+		return changed
+	}
+
+	changed := false
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, x := range v {
+			if head == "*" || head == key {
+				if len(tail) == 0 {
+					v[key], changed = Redacted, true
+				} else {
+					changed = redactPath(x, tail) || changed
+				}
+			}
+		}
+	case []interface{}:
+		for i, x := range v {
+			if head == "*" || head == strconv.Itoa(i) {
+				if len(tail) == 0 {
+					v[i], changed = Redacted, true
+				} else {
+					changed = redactPath(x, tail) || changed
+				}
+			}
+		}
+	}
+	return changed
+}
+
+/*
+
+RedactRegexp hides matches of regular expression at body and header values.
+The whole match is hidden unless the expression defines capture groups,
+only captured text is hidden otherwise.
+*/
+func RedactRegexp(re *regexp.Regexp) Redaction {
+	redact := func(text []byte) []byte {
+		if re.NumSubexp() == 0 {
+			return re.ReplaceAll(text, []byte(Redacted))
+		}
+
+		var buf bytes.Buffer
+		last := 0
+		for _, at := range re.FindAllSubmatchIndex(text, -1) {
+			for i := 2; i < len(at); i += 2 {
+				if at[i] < last || at[i] < 0 {
+					continue
+				}
+				buf.Write(text[last:at[i]])
+				buf.WriteString(Redacted)
+				last = at[i+1]
+			}
+		}
+		buf.Write(text[last:])
+		return buf.Bytes()
+	}
+
+	return func(e *Event) {
+		if len(e.Body) > 0 {
+			e.Body = redact(e.Body)
+		}
+
+		for header, values := range e.Header {
+			for i, value := range values {
+				e.Header[header][i] = string(redact([]byte(value)))
+			}
+		}
+	}
+}
+
+func (cat *IOCat) rules() []Redaction {
+	if cat.redactions == nil {
+		return DefaultRedactions()
+	}
+	return cat.redactions
+}
+
+func (cat *IOCat) redact(e *Event) {
+	for _, redact := range cat.rules() {
+		redact(e)
+	}
+}
+
+/*
+
+RedactFail hides sensitive data of Mismatch failure using redaction rules of
+category. The rules are applied to the payload, which is treated as JSON
+document, and to the diff. The payload is replaced only if rules change it,
+the redacted payload keeps its type if it is decodable. Join combinators
+apply it on failures.
+*/
+func (cat *IOCat) RedactFail() *IOCat {
+	var e *Mismatch
+	if !errors.As(cat.Fail, &e) {
+		return cat
+	}
+
+	if e.Payload != nil {
+		if data, err := json.Marshal(e.Payload); err == nil {
+			event := &Event{Header: http.Header{}, Body: data}
+			if cat.redact(event); !bytes.Equal(event.Body, data) {
+				e.Payload = retype(e.Payload, event.Body)
+			}
+		}
+	}
+
+	event := &Event{Header: http.Header{}, Body: []byte(e.Diff)}
+	cat.redact(event)
+	e.Diff = string(event.Body)
+
+	return cat
+}
+
+// retype decodes redacted payload into the type of original one, generic
+// JSON document or text is used if redacted values do not fit the type.
+func retype(origin interface{}, data []byte) interface{} {
+	t := reflect.TypeOf(origin)
+	if t.Kind() == reflect.Ptr {
+		if value := reflect.New(t.Elem()); json.Unmarshal(data, value.Interface()) == nil {
+			return value.Interface()
+		}
+	} else {
+		if value := reflect.New(t); json.Unmarshal(data, value.Interface()) == nil {
+			return value.Elem().Interface()
+		}
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return string(data)
+	}
+	return payload
+}
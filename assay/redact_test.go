@@ -0,0 +1,157 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/google/go-cmp/cmp"
+)
+
+func capture(opts ...assay.Config) (*assay.IOCat, *[]*assay.Event) {
+	seq := []*assay.Event{}
+	logger := assay.LoggerFunc(func(e *assay.Event) { seq = append(seq, e) })
+	return assay.IO(append(opts, assay.LogWith(logger))...), &seq
+}
+
+func TestRedactDefaults(t *testing.T) {
+	c, seq := capture()
+
+	uri, _ := url.Parse("https://example.com/?client_secret=xxx&scope=a")
+	c.Log(&assay.Event{
+		URL: uri,
+		Header: http.Header{
+			"Authorization": {"Bearer xxx"},
+			"Cookie":        {"session=xxx"},
+			"X-Token":       {"Bearer xxx"},
+		},
+		Body: []byte(`{"client_secret":"xxx","user":{"password":"xxx","name":"a"},"plan":"basic tier"}`),
+	})
+
+	e := (*seq)[0]
+	if e.Header.Get("Authorization") != assay.Redacted ||
+		e.Header.Get("Cookie") != assay.Redacted ||
+		e.Header.Get("X-Token") != "Bearer xxx" {
+		t.Errorf("headers are not redacted %v", e.Header)
+	}
+
+	if e.URL.Query().Get("client_secret") != assay.Redacted || e.URL.Query().Get("scope") != "a" {
+		t.Errorf("query is not redacted %v", e.URL)
+	}
+
+	if string(e.Body) != `{"client_secret":"[REDACTED]","plan":"basic tier","user":{"name":"a","password":"[REDACTED]"}}` {
+		t.Errorf("body is not redacted %s", e.Body)
+	}
+}
+
+func TestRedactAuthorization(t *testing.T) {
+	for _, tc := range []struct{ text, expect string }{
+		{"+ Authorization: Bearer xxx", "+ Authorization: Bearer [REDACTED]"},
+		{"Proxy-Authorization: basic eHh4Onl5eQ==", "Proxy-Authorization: basic [REDACTED]"},
+		{`{"Authorization":"Bearer xxx"}`, `{"Authorization":"Bearer [REDACTED]"}`},
+		{"+ plan: basic tier", "+ plan: basic tier"},
+		{"Bearer of good news", "Bearer of good news"},
+	} {
+		c, seq := capture()
+		c.Log(&assay.Event{Header: http.Header{}, Body: []byte(tc.text)})
+
+		if body := string((*seq)[0].Body); body != tc.expect {
+			t.Errorf("unexpected redaction of %s: %s", tc.text, body)
+		}
+	}
+}
+
+func TestNoRedaction(t *testing.T) {
+	c, seq := capture(assay.NoRedaction())
+
+	c.Log(&assay.Event{
+		Header: http.Header{"Authorization": {"Bearer xxx"}},
+	})
+
+	if (*seq)[0].Header.Get("Authorization") != "Bearer xxx" {
+		t.Error("header is redacted")
+	}
+}
+
+func TestRedactField(t *testing.T) {
+	for _, tc := range []struct{ path, body, expect string }{
+		{"a.b", `{"a":{"b":1,"c":2}}`, `{"a":{"b":"[REDACTED]","c":2}}`},
+		{"a.*.b", `{"a":[{"b":1},{"c":2}]}`, `{"a":[{"b":"[REDACTED]"},{"c":2}]}`},
+		{"a.1", `{"a":[1,2]}`, `{"a":[1,"[REDACTED]"]}`},
+		{"**.b", `[{"a":{"b":1}},{"b":2}]`, `[{"a":{"b":"[REDACTED]"}},{"b":"[REDACTED]"}]`},
+		{"b", `{"a":{"b":1}}`, `{"a":{"b":1}}`},
+		{"secret", `id=a&secret=b&c=d`, `id=a&secret=[REDACTED]&c=d`},
+		{"**.secret", `secret=b`, `secret=[REDACTED]`},
+	} {
+		e := &assay.Event{Body: []byte(tc.body)}
+		assay.RedactField(tc.path)(e)
+		if string(e.Body) != tc.expect {
+			t.Errorf("unexpected redaction of %s at %s: %s", tc.path, tc.body, e.Body)
+		}
+	}
+}
+
+func TestRedactRegexp(t *testing.T) {
+	e := &assay.Event{Body: []byte(`token=abc; key=def`)}
+	assay.RedactRegexp(regexp.MustCompile(`(?:token|key)=(\w+)`))(e)
+	if string(e.Body) != `token=[REDACTED]; key=[REDACTED]` {
+		t.Errorf("unexpected redaction %s", e.Body)
+	}
+
+	e = &assay.Event{Body: []byte(`a 1234 b`)}
+	assay.RedactRegexp(regexp.MustCompile(`\d+`))(e)
+	if string(e.Body) != `a [REDACTED] b` {
+		t.Errorf("unexpected redaction %s", e.Body)
+	}
+}
+
+func TestRedactFail(t *testing.T) {
+	type Token struct {
+		AccessToken string `json:"access_token"`
+		Type        string `json:"token_type"`
+	}
+
+	c := assay.Join(
+		failWith(&assay.Mismatch{
+			Diff:    "+ Authorization: Bearer xxx",
+			Payload: Token{"xxx", "bearer"},
+		}),
+	)(assay.IO())
+
+	e := c.Fail.(*assay.Mismatch)
+	if e.Diff != "+ Authorization: Bearer "+assay.Redacted {
+		t.Errorf("diff is not redacted %s", e.Diff)
+	}
+
+	expect := Token{assay.Redacted, "bearer"}
+	if diff := cmp.Diff(e.Payload, expect); diff != "" {
+		t.Errorf("payload is not redacted %s", diff)
+	}
+}
+
+func TestRedactFailUnchanged(t *testing.T) {
+	type Plan struct {
+		Name string `json:"name"`
+		Tier string `json:"tier"`
+	}
+
+	plan := &Plan{"a", "basic tier"}
+	c := assay.Join(
+		failWith(&assay.Mismatch{Diff: "+ tier: basic tier", Payload: plan}),
+	)(assay.IO())
+
+	e := c.Fail.(*assay.Mismatch)
+	if e.Payload != plan || e.Diff != "+ tier: basic tier" {
+		t.Errorf("mismatch is changed %v %s", e.Payload, e.Diff)
+	}
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+/*
+
+Backoff defines the delay before next attempt, attempts are counted from 1.
+*/
+type Backoff func(attempt int) time.Duration
+
+/*
+
+Constant backoff delays each attempt with same interval.
+*/
+func Constant(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+/*
+
+Exponential backoff doubles the delay on each attempt, starting with base
+interval. The delay never exceeds the max interval.
+*/
+func Exponential(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+/*
+
+Jitter randomizes delays of backoff, it keeps a half of original delay and
+adds a random value from the remaining half.
+*/
+func Jitter(backoff Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt) / 2
+		if delay <= 0 {
+			return backoff(attempt)
+		}
+		return delay + time.Duration(rand.Int63n(int64(delay)))
+	}
+}
+
+/*
+
+Policy defines parameters of Retry combinator.
+  - Backoff defines the delay between attempts, no delay if not defined
+  - Attempts limits the number of attempts
+  - Elapsed limits the total time spent on attempts
+  - Retryable defines failures to be retried, any failure is retried if not defined
+
+The policy makes 3 attempts if neither Attempts nor Elapsed is defined.
+*/
+type Policy struct {
+	Backoff   Backoff
+	Attempts  int
+	Elapsed   time.Duration
+	Retryable func(error) bool
+}
+
+func (policy Policy) exhausted(attempt int, started time.Time) bool {
+	if policy.Attempts <= 0 && policy.Elapsed <= 0 {
+		return attempt >= 3
+	}
+
+	if policy.Attempts > 0 && attempt >= policy.Attempts {
+		return true
+	}
+
+	if policy.Elapsed > 0 {
+		elapsed := time.Since(started) + policy.delay(attempt)
+		return elapsed > policy.Elapsed
+	}
+
+	return false
+}
+
+func (policy Policy) delay(attempt int) time.Duration {
+	if policy.Backoff == nil {
+		return 0
+	}
+	return policy.Backoff(attempt)
+}
+
+func (policy Policy) retryable(err error) bool {
+	if policy.Retryable == nil {
+		return true
+	}
+	return policy.Retryable(err)
+}
+
+/*
+
+Retry re-evaluates the composition of arrows until it succeeds or the policy
+is exhausted. HTTP state of category is reset before each attempt. The
+evaluation fails with Exhausted error, which reports the last failure and
+number of attempts. The failure not accepted by Retryable predicate is
+returned as-is without further attempts.
+  assay.Retry(
+    assay.Policy{
+      Backoff:  assay.Jitter(assay.Exponential(100*time.Millisecond, 5*time.Second)),
+      Attempts: 10,
+      Retryable: func(err error) bool {
+        var code http.StatusCode
+        return errors.As(err, &code) && code.Value() >= 500
+      },
+    },
+    http.Join(...),
+  )
+*/
+func Retry(policy Policy, arrows ...Arrow) Arrow {
+	f := Join(arrows...)
+
+	return func(cat *IOCat) *IOCat {
+		started := time.Now()
+
+		for attempt := 1; ; attempt++ {
+			if cat.Done() {
+				return cat
+			}
+
+			cat.Reset()
+			if cat = f(cat); cat.Fail == nil {
+				return cat
+			}
+
+			if cat.Context().Err() != nil || !policy.retryable(cat.Fail) {
+				return cat
+			}
+
+			if policy.exhausted(attempt, started) {
+				cat.Fail = &Exhausted{Attempts: attempt, Err: cat.Fail}
+				return cat
+			}
+
+			select {
+			case <-time.After(policy.delay(attempt)):
+				cat.Fail = nil
+			case <-cat.Context().Done():
+				cat.Fail = nil
+				cat.Done()
+				return cat
+			}
+		}
+	}
+}
+
+// Exhausted is returned if Retry combinator exhausts its policy
+type Exhausted struct {
+	Attempts int
+	Err      error
+}
+
+func (e *Exhausted) Error() string {
+	return fmt.Sprintf("Exhausted after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the last failure
+func (e *Exhausted) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+func failN(n int, attempts *int) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		if cat.HTTP != nil {
+			cat.Fail = errors.New("dirty state")
+			return cat
+		}
+
+		*attempts++
+		cat.HTTP = &assay.IOCatHTTP{}
+		if *attempts <= n {
+			cat.Fail = &assay.Undefined{Type: "attempt"}
+		}
+		return cat
+	}
+}
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	f := assay.Retry(
+		assay.Policy{Backoff: assay.Constant(time.Millisecond), Attempts: 5},
+		failN(3, &attempts),
+	)
+
+	if c := f(assay.IO()); c.Fail != nil || attempts != 4 {
+		t.Error("retry is failed")
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	attempts := 0
+	f := assay.Retry(
+		assay.Policy{Attempts: 2},
+		failN(3, &attempts),
+	)
+
+	c := f(assay.IO())
+
+	var e *assay.Exhausted
+	if !errors.As(c.Fail, &e) || e.Attempts != 2 || attempts != 2 {
+		t.Error("retry is not exhausted")
+	}
+
+	var u *assay.Undefined
+	if !errors.As(c.Fail, &u) {
+		t.Error("retry do not report last failure")
+	}
+}
+
+func TestRetryElapsed(t *testing.T) {
+	attempts := 0
+	f := assay.Retry(
+		assay.Policy{Backoff: assay.Constant(20 * time.Millisecond), Elapsed: 50 * time.Millisecond},
+		failN(10, &attempts),
+	)
+
+	var e *assay.Exhausted
+	if c := f(assay.IO()); !errors.As(c.Fail, &e) || attempts >= 10 {
+		t.Error("retry is not exhausted")
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	attempts := 0
+	f := assay.Retry(
+		assay.Policy{
+			Attempts: 5,
+			Retryable: func(err error) bool {
+				var e *assay.Mismatch
+				return errors.As(err, &e)
+			},
+		},
+		failN(3, &attempts),
+	)
+
+	var e *assay.Undefined
+	if c := f(assay.IO()); !errors.As(c.Fail, &e) || attempts != 1 {
+		t.Error("retry of non-retryable failure")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	exp := assay.Exponential(10*time.Millisecond, 50*time.Millisecond)
+	for attempt, delay := range []time.Duration{10, 20, 40, 50, 50} {
+		if exp(attempt+1) != delay*time.Millisecond {
+			t.Errorf("unexpected exponential backoff %v at %d", exp(attempt+1), attempt+1)
+		}
+	}
+
+	jit := assay.Jitter(assay.Constant(10 * time.Millisecond))
+	for i := 0; i < 100; i++ {
+		if d := jit(1); d < 5*time.Millisecond || d >= 10*time.Millisecond {
+			t.Errorf("unexpected jitter %v", d)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import "log/slog"
+
+/*
+
+Slog adapts structured logger from log/slog package to Logger interface.
+Events are logged at Info level with the type of event as a message.
+*/
+func Slog(logger *slog.Logger) Logger {
+	return LoggerFunc(func(e *Event) {
+		attrs := []any{
+			slog.String("method", e.Method),
+			slog.String("url", e.URL.String()),
+			slog.Int64("size", e.Size),
+		}
+
+		if e.Type == EventIngress {
+			attrs = append(attrs,
+				slog.Int("status", e.Status),
+				slog.Duration("duration", e.Duration),
+			)
+		}
+
+		if len(e.Header) > 0 {
+			attrs = append(attrs, slog.Any("header", e.Header))
+		}
+
+		if len(e.Body) > 0 {
+			attrs = append(attrs, slog.String("body", string(e.Body)))
+		}
+
+		logger.Info(e.Type, attrs...)
+	})
+}
@@ -0,0 +1,30 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+func TestSlog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := assay.IO(assay.LogWith(assay.Slog(slog.New(slog.NewTextHandler(buf, nil)))))
+
+	c.Log(event())
+	for _, attr := range []string{"msg=egress", "method=GET", "url=\"https://example.com/a?b=c\"", "body="} {
+		if !strings.Contains(buf.String(), attr) {
+			t.Errorf("attribute %s is not logged: %s", attr, buf.String())
+		}
+	}
+}
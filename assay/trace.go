@@ -0,0 +1,160 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+/*
+
+Step is an evaluation of arrow within the composition. Index is a position
+of arrow in the composition (starts from 1), Depth is a nesting level of
+composition.
+*/
+type Step struct {
+	Label string
+	Index int
+	Depth int
+	Start time.Time
+	End   time.Time
+	Fail  error
+}
+
+// Duration of the step
+func (s Step) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// String formats the step for humans, e.g. step 5 recv.Code: HTTP 404, required 200
+func (s Step) String() string {
+	if s.Fail == nil {
+		return fmt.Sprintf("step %d %s", s.Index, s.Label)
+	}
+	return fmt.Sprintf("step %d %s: %v", s.Index, s.Label, s.Fail)
+}
+
+/*
+
+Trace is a sequence of steps recorded by the category in the order of
+evaluation. Nested steps follow the step of enclosing composition.
+*/
+type Trace []Step
+
+/*
+
+Failed returns the innermost step that failed last. The step is looked up
+from the outermost failed step through its failed nested steps, failures
+recovered by enclosing composition (e.g. Retry, Alt) are ignored.
+*/
+func (trace Trace) Failed() (Step, bool) {
+	failed := -1
+	for from, to, depth := 0, len(trace), 0; ; depth++ {
+		last := -1
+		for i := from; i < to; i++ {
+			if trace[i].Depth == depth && trace[i].Fail != nil {
+				last = i
+			}
+		}
+		if last == -1 {
+			break
+		}
+
+		failed, from, to = last, last+1, last+1
+		for to < len(trace) && trace[to].Depth > depth {
+			to++
+		}
+	}
+
+	if failed == -1 {
+		return Step{}, false
+	}
+	return trace[failed], true
+}
+
+// tracer is the state of step-level tracing
+type tracer struct {
+	enabled bool
+	steps   Trace
+	current int
+	depth   int
+}
+
+/*
+
+Trace returns steps recorded by the category, tracing is enabled with
+Tracing config.
+*/
+func (cat *IOCat) Trace() Trace {
+	return cat.trace.steps
+}
+
+// apply evaluates i-th arrow of composition, the step is recorded if tracing is enabled
+func (cat *IOCat) apply(i int, f Arrow) *IOCat {
+	if !cat.trace.enabled {
+		return f(cat)
+	}
+
+	id := len(cat.trace.steps)
+	cat.trace.steps = append(cat.trace.steps,
+		Step{Label: label(f), Index: i, Depth: cat.trace.depth, Start: time.Now()},
+	)
+
+	parent := cat.trace.current
+	cat.trace.current, cat.trace.depth = id+1, cat.trace.depth+1
+	cat = f(cat)
+	cat.trace.current, cat.trace.depth = parent, cat.trace.depth-1
+
+	cat.trace.steps[id].End = time.Now()
+	cat.trace.steps[id].Fail = cat.Fail
+	return cat
+}
+
+/*
+
+Label names the step of composition. By default, the step is labelled with
+the name of function that has built the arrow (e.g. recv.Code). Toolchains
+before Go 1.27 prefix the name with the caller if the function is inlined
+(e.g. main.TestUser.Code), use Label to name steps regardless of toolchain.
+  assay.Join(
+    assay.Label("create user", http.Join(...)),
+    assay.Label("lookup user", http.Join(...)),
+  )
+*/
+func Label(label string, f Arrow) Arrow {
+	return func(cat *IOCat) *IOCat {
+		if cat.trace.enabled && cat.trace.current > 0 {
+			cat.trace.steps[cat.trace.current-1].Label = label
+		}
+		return f(cat)
+	}
+}
+
+// closure suffix of function name, e.g. .func1 or .func1.2
+var closure = regexp.MustCompile(`(\.func\d+|\.\d+)+$`)
+
+// label of arrow is the name of function that has built it
+func label(f Arrow) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return "arrow"
+	}
+
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	name = closure.ReplaceAllString(name, "")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
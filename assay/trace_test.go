@@ -0,0 +1,113 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package assay_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+// labelled matches the label of step, toolchains before Go 1.27 prefix
+// closures of inlined functions with the caller, e.g. pkg.TestX.identity
+func labelled(label, expect string) bool {
+	name := expect[strings.LastIndex(expect, ".")+1:]
+	return label == expect || strings.HasSuffix(label, "."+name)
+}
+
+func TestTrace(t *testing.T) {
+	f := assay.Join(
+		identity(),
+		assay.Join(identity(), assay.Label("failure", fail())),
+		identity(),
+	)
+
+	cat := f(assay.IO(assay.Tracing()))
+	trace := cat.Trace()
+
+	if len(trace) != 4 {
+		t.Fatalf("unexpected steps %v", trace)
+	}
+
+	for i, expect := range []struct {
+		label string
+		index int
+		depth int
+	}{
+		{"assay_test.identity", 1, 0},
+		{"assay.Join", 2, 0},
+		{"assay_test.identity", 1, 1},
+		{"failure", 2, 1},
+	} {
+		step := trace[i]
+		if !labelled(step.Label, expect.label) || step.Index != expect.index || step.Depth != expect.depth {
+			t.Errorf("unexpected step %d: %+v", i, step)
+		}
+	}
+
+	step, ok := trace.Failed()
+	if !ok || step.String() != "step 2 failure: fail" {
+		t.Errorf("unexpected failed step %v", step)
+	}
+}
+
+func TestTraceThen(t *testing.T) {
+	f := identity().Then(identity(), fail())
+
+	cat := f(assay.IO(assay.Tracing()))
+	if step, ok := cat.Trace().Failed(); !ok || step.Index != 3 {
+		t.Errorf("unexpected failed step %v", step)
+	}
+}
+
+func TestTraceRecovered(t *testing.T) {
+	attempts := 0
+	f := assay.Join(
+		assay.Retry(assay.Policy{Attempts: 2}, assay.Label("flaky", failN(1, &attempts))),
+		assay.Label("failure", fail()),
+	)
+
+	cat := f(assay.IO(assay.Tracing()))
+	if step, ok := cat.Trace().Failed(); !ok || step.Label != "failure" {
+		t.Errorf("unexpected failed step %v", step)
+	}
+
+	attempts = 0
+	slow := func(cat *assay.IOCat) *assay.IOCat {
+		time.Sleep(20 * time.Millisecond)
+		return cat
+	}
+	f = assay.Join(
+		assay.Label("deadline",
+			assay.Deadline(10*time.Millisecond,
+				assay.Retry(assay.Policy{Attempts: 2}, assay.Label("flaky", failN(1, &attempts))),
+				slow,
+			),
+		),
+	)
+
+	cat = f(assay.IO(assay.Tracing()))
+	if step, ok := cat.Trace().Failed(); !ok || step.Label != "deadline" {
+		t.Errorf("unexpected failed step %v", step)
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	cat := assay.Join(identity(), fail())(assay.IO())
+
+	if len(cat.Trace()) != 0 {
+		t.Error("steps are traced")
+	}
+
+	if fork := assay.IO(assay.Tracing()).Fork(); len(fork.Trace()) != 0 {
+		t.Error("steps are shared with fork")
+	}
+}
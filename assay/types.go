@@ -9,9 +9,15 @@
 package assay
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/url"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 /*
@@ -22,23 +28,94 @@ type IOCat struct {
 	Fail       error
 	HTTP       *IOCatHTTP
 	LogLevel   int
+	ctx        context.Context
+	loggers    []Logger
+	observers  []observe
+	redactions []Redaction
 	sideEffect Arrow
+	trace      tracer
+
+	interceptors []Interceptor
 }
 
 /*
 
-Unsafe applies a side effect on the category
+Context returns the context of category, the background context is
+returned if category is not configured with one.
 */
-func (cat *IOCat) Unsafe() *IOCat {
-	if cat.sideEffect != nil {
-		return cat.sideEffect(cat)
+func (cat *IOCat) Context() context.Context {
+	if cat.ctx == nil {
+		return context.Background()
 	}
-	cat.Fail = fmt.Errorf("Undefined side effect")
+	return cat.ctx
+}
+
+/*
+
+Done checks if the context of category is cancelled or expired. It fails
+the category with Canceled error if context is done, so that composition
+of arrows stops at earliest.
+*/
+func (cat *IOCat) Done() bool {
+	if cat.ctx == nil {
+		return false
+	}
+
+	if err := cat.ctx.Err(); err != nil {
+		if cat.Fail == nil {
+			cat.Fail = &Canceled{Err: err}
+		}
+		return true
+	}
+	return false
+}
+
+/*
+
+Reset discards any unconsumed HTTP response and cleans HTTP state of category.
+*/
+func (cat *IOCat) Reset() *IOCat {
+	if cat.HTTP != nil && cat.HTTP.Recv != nil && cat.HTTP.Recv.Response != nil {
+		io.Copy(ioutil.Discard, cat.HTTP.Recv.Body)
+		cat.HTTP.Recv.Body.Close()
+	}
+	cat.HTTP = nil
 	return cat
 }
 
 /*
 
+Fork creates a copy of category. The copy shares configuration with origin
+(e.g. context, logging and side effect) but has independent I/O state.
+*/
+func (cat *IOCat) Fork() *IOCat {
+	fork := *cat
+	fork.Fail = nil
+	fork.HTTP = nil
+	fork.trace = tracer{enabled: cat.trace.enabled}
+	return &fork
+}
+
+/*
+
+Unsafe applies a side effect on the category, the side effect is wrapped
+by interceptors of category.
+*/
+func (cat *IOCat) Unsafe() *IOCat {
+	if cat.sideEffect == nil {
+		cat.Fail = fmt.Errorf("Undefined side effect")
+		return cat
+	}
+
+	f := cat.sideEffect
+	for i := len(cat.interceptors) - 1; i >= 0; i-- {
+		f = cat.interceptors[i](f)
+	}
+	return f(cat)
+}
+
+/*
+
 Recover any fail state of I/O category
 */
 func (cat *IOCat) Recover() (err error) {
@@ -67,10 +144,13 @@ Join composes arrows to high-order function
 */
 func Join(arrows ...Arrow) Arrow {
 	return func(cat *IOCat) *IOCat {
-		for _, f := range arrows {
-			if cat = f(cat); cat.Fail != nil {
+		for i, f := range arrows {
+			if cat.Done() {
 				return cat
 			}
+			if cat = cat.apply(i+1, f); cat.Fail != nil {
+				return cat.RedactFail()
+			}
 		}
 		return cat
 	}
@@ -82,14 +162,124 @@ Then is an alias for Join
 */
 func (head Arrow) Then(arrows ...Arrow) Arrow {
 	return func(cat *IOCat) *IOCat {
-		if cat = head(cat); cat.Fail != nil {
+		if cat.Done() {
 			return cat
 		}
 
-		for _, f := range arrows {
-			if cat = f(cat); cat.Fail != nil {
+		if cat = cat.apply(1, head); cat.Fail != nil {
+			return cat.RedactFail()
+		}
+
+		for i, f := range arrows {
+			if cat.Done() {
 				return cat
 			}
+			if cat = cat.apply(i+2, f); cat.Fail != nil {
+				return cat.RedactFail()
+			}
+		}
+		return cat
+	}
+}
+
+/*
+
+Deadline bounds the composition of arrows with the time limit. The evaluation
+fails with Timeout error if arrows are not completed in time, even if arrows
+ignore the context and succeed after the limit. It fails with Canceled error
+if the parent context is done before the limit.
+  assay.Deadline(5*time.Second,
+    http.Join(...),
+    http.Join(...),
+  )
+*/
+func Deadline(timeout time.Duration, arrows ...Arrow) Arrow {
+	f := Join(arrows...)
+
+	return func(cat *IOCat) *IOCat {
+		if cat.Done() {
+			return cat
+		}
+
+		parent := cat.ctx
+		ctx, cancel := context.WithTimeout(cat.Context(), timeout)
+		defer cancel()
+
+		cat.ctx = ctx
+		cat = f(cat)
+		cat.ctx = parent
+
+		switch {
+		case parent != nil && parent.Err() != nil:
+			cat.Fail = &Canceled{Err: parent.Err()}
+		case ctx.Err() == context.DeadlineExceeded:
+			cat.Fail = &Timeout{Duration: timeout}
+		}
+		return cat
+	}
+}
+
+/*
+
+Parallel evaluates arrows concurrently, each arrow is applied to own fork of
+the category. The evaluation fails with Compound error, which merges failures
+of all arrows.
+  assay.Parallel(
+    http.Join(...),
+    http.Join(...),
+  )
+*/
+func Parallel(arrows ...Arrow) Arrow {
+	return ParallelN(len(arrows), arrows...)
+}
+
+/*
+
+ParallelN evaluates arrows concurrently like Parallel but it limits the number
+of arrows evaluated at once.
+*/
+func ParallelN(n int, arrows ...Arrow) Arrow {
+	if n <= 0 {
+		n = 1
+	}
+
+	return func(cat *IOCat) *IOCat {
+		if cat.Done() {
+			return cat
+		}
+
+		fails := make([]error, len(arrows))
+		slots := make(chan struct{}, n)
+
+		var wg sync.WaitGroup
+		for i, f := range arrows {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(i int, f Arrow) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				fork := cat.Fork()
+				if fork.Done() {
+					fails[i] = fork.Fail
+					return
+				}
+				fork = f(fork)
+				fork.Reset()
+				fails[i] = fork.Fail
+			}(i, f)
+		}
+		wg.Wait()
+
+		seq := []error{}
+		for _, err := range fails {
+			if err != nil {
+				seq = append(seq, err)
+			}
+		}
+
+		if len(seq) > 0 {
+			cat.Fail = &Compound{Errors: seq}
 		}
 		return cat
 	}
@@ -115,6 +305,57 @@ func (e *NotSupported) Error() string {
 	return fmt.Sprintf("Not supported: %s", e.URL.String())
 }
 
+// Canceled is returned if context of category is done before evaluation is completed.
+type Canceled struct{ Err error }
+
+func (e *Canceled) Error() string {
+	return fmt.Sprintf("Canceled: %v", e.Err)
+}
+
+// Unwrap returns the context error, it makes errors.Is(err, context.Canceled) usable
+func (e *Canceled) Unwrap() error {
+	return e.Err
+}
+
+// Timeout is returned if evaluation is not completed within the time limit.
+type Timeout struct{ Duration time.Duration }
+
+func (e *Timeout) Error() string {
+	return fmt.Sprintf("Timeout: not completed within %v", e.Duration)
+}
+
+// Unwrap returns context.DeadlineExceeded, it makes errors.Is usable
+func (e *Timeout) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Compound is returned if multiple arrows fails, e.g. evaluated in parallel.
+type Compound struct{ Errors []error }
+
+func (e *Compound) Error() string {
+	msg := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msg[i] = err.Error()
+	}
+	return fmt.Sprintf("Failed %d arrows:\n%s", len(e.Errors), strings.Join(msg, "\n"))
+}
+
+// Unwrap returns failures of arrows, it makes errors.Is and errors.As usable
+func (e *Compound) Unwrap() []error {
+	return e.Errors
+}
+
+// OverBudget is returned if latency of request exceeds the budget
+type OverBudget struct {
+	Metric string
+	Budget time.Duration
+	Actual time.Duration
+}
+
+func (e *OverBudget) Error() string {
+	return fmt.Sprintf("Latency %s %v exceeds budget %v", e.Metric, e.Actual, e.Budget)
+}
+
 // Mismatch is returned by api if expectation at body value is failed
 type Mismatch struct {
 	Diff    string
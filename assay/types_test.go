@@ -9,8 +9,11 @@
 package assay_test
 
 import (
+	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/assay-it/sdk-go/assay"
 )
@@ -28,6 +31,17 @@ func fail() assay.Arrow {
 	}
 }
 
+func sleep(d time.Duration) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		select {
+		case <-time.After(d):
+		case <-cat.Context().Done():
+			cat.Fail = cat.Context().Err()
+		}
+		return cat
+	}
+}
+
 func TestJoin(t *testing.T) {
 	for _, f := range []assay.Arrow{
 		assay.Join(identity(), identity()),
@@ -52,6 +66,155 @@ func TestJoinFail(t *testing.T) {
 	}
 }
 
+func TestJoinCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, f := range []assay.Arrow{
+		assay.Join(identity(), identity()),
+		identity().Then(identity()),
+	} {
+		c := f(assay.IO(assay.Context(ctx)))
+		var e *assay.Canceled
+		if !errors.As(c.Fail, &e) || !errors.Is(c.Fail, context.Canceled) {
+			t.Error("join is not canceled")
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	c := assay.Deadline(100*time.Millisecond, identity(), sleep(time.Millisecond))(assay.IO())
+	if c.Fail != nil {
+		t.Error("deadline is failed")
+	}
+}
+
+func TestDeadlineTimeout(t *testing.T) {
+	c := assay.Deadline(10*time.Millisecond, identity(), sleep(time.Second))(assay.IO())
+
+	var e *assay.Timeout
+	if !errors.As(c.Fail, &e) || !errors.Is(c.Fail, context.DeadlineExceeded) {
+		t.Error("deadline is not timed out")
+	}
+
+	if c.Context().Err() != nil {
+		t.Error("deadline leaks context")
+	}
+}
+
+func TestDeadlineIgnored(t *testing.T) {
+	slow := func(cat *assay.IOCat) *assay.IOCat {
+		time.Sleep(50 * time.Millisecond)
+		return cat
+	}
+	c := assay.Deadline(10*time.Millisecond, slow)(assay.IO())
+
+	var e *assay.Timeout
+	if !errors.As(c.Fail, &e) {
+		t.Error("deadline is not timed out")
+	}
+}
+
+func TestDeadlineCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c := assay.Deadline(time.Hour, sleep(time.Second))(assay.IO(assay.Context(ctx)))
+
+	var e *assay.Canceled
+	if !errors.As(c.Fail, &e) || !errors.Is(c.Fail, context.DeadlineExceeded) {
+		t.Errorf("deadline is not canceled by parent: %v", c.Fail)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	f := assay.Parallel(identity(), sleep(10*time.Millisecond), identity())
+	if c := f(assay.IO()); c.Fail != nil {
+		t.Error("parallel is failed")
+	}
+}
+
+func TestParallelFail(t *testing.T) {
+	f := assay.Parallel(identity(), fail(), identity(), fail())
+	c := f(assay.IO())
+
+	var e *assay.Compound
+	if !errors.As(c.Fail, &e) || len(e.Errors) != 2 {
+		t.Error("parallel do not merge failures")
+	}
+}
+
+func TestCompound(t *testing.T) {
+	timeout := &assay.Timeout{Duration: time.Second}
+	e := &assay.Compound{Errors: []error{errors.New("fail"), timeout}}
+
+	var x *assay.Timeout
+	if !errors.Is(e, context.DeadlineExceeded) || !errors.As(e, &x) || x != timeout {
+		t.Error("compound do not match failures")
+	}
+
+	if errors.Is(e, context.Canceled) || errors.As(e, new(*assay.Canceled)) {
+		t.Error("compound matches unknown failure")
+	}
+}
+
+func TestParallelN(t *testing.T) {
+	var active, peak int32
+	track := func(cat *assay.IOCat) *assay.IOCat {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return cat
+	}
+
+	f := assay.ParallelN(2, track, track, track, track, track)
+	if c := f(assay.IO()); c.Fail != nil || peak > 2 {
+		t.Error("parallel is not bounded")
+	}
+}
+
+func TestFork(t *testing.T) {
+	c := assay.IO(assay.Logging(assay.LogLevelDebug))
+	c.HTTP = &assay.IOCatHTTP{}
+	c.Fail = errors.New("fail")
+
+	fork := c.Fork()
+	if fork.LogLevel != assay.LogLevelDebug || fork.HTTP != nil || fork.Fail != nil {
+		t.Error("fork is failed")
+	}
+}
+
+func TestIntercept(t *testing.T) {
+	seq := ""
+	wrap := func(val string) assay.Interceptor {
+		return func(f assay.Arrow) assay.Arrow {
+			return func(cat *assay.IOCat) *assay.IOCat {
+				seq += val
+				return f(cat)
+			}
+		}
+	}
+
+	c := assay.IO(
+		assay.Intercept(wrap("a")),
+		assay.SideEffect(func(cat *assay.IOCat) *assay.IOCat {
+			seq += "x"
+			return cat
+		}),
+		assay.Intercept(wrap("b")),
+	)
+
+	if c = c.Unsafe(); c.Fail != nil || seq != "abx" {
+		t.Errorf("unexpected order of interceptors %s", seq)
+	}
+}
+
 func TestRecover(t *testing.T) {
 	c := assay.IO()
 
@@ -0,0 +1,62 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/assay-it/sdk-go/http/openapi"
+)
+
+// generate emits scenario skeletons from OpenAPI document
+func generate(args []string) int {
+	fs := flag.NewFlagSet("assay generate", flag.ContinueOnError)
+	output := fs.String("o", "", "output file of Go source, default stdout")
+	pkg := fs.String("package", "suites", "package name of Go source")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: assay generate [-o file] [-package name] openapi.yaml\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := scaffold(fs.Arg(0), *output, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func scaffold(input, output, pkg string) error {
+	contract, err := openapi.Load(input)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return contract.Generate(w, pkg)
+}
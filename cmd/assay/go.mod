@@ -0,0 +1,25 @@
+module github.com/assay-it/sdk-go/cmd/assay
+
+go 1.25.0
+
+require (
+	github.com/assay-it/sdk-go v0.0.0-00010101000000-000000000000
+	github.com/assay-it/sdk-go/http/openapi v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/getkin/kin-openapi v0.149.0 // indirect
+	github.com/go-openapi/jsonpointer v0.22.5 // indirect
+	github.com/go-openapi/swag/jsonname v0.25.5 // indirect
+	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/oasdiff/yaml v0.1.1 // indirect
+	github.com/oasdiff/yaml3 v0.0.14 // indirect
+	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace (
+	github.com/assay-it/sdk-go => ../..
+	github.com/assay-it/sdk-go/http/openapi => ../../http/openapi
+)
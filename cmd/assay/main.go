@@ -0,0 +1,162 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Command assay runs compiled Behavior as a Code suites. The suite package
+registers its scenarios using suite.Register, the command builds the runner
+for the package and executes scenarios against the target.
+
+  assay -target https://example.com -run Health -parallel 4 ./suites
+  assay -target https://example.com -tags "smoke && !destructive" ./suites
+
+The command should be executed within the Go module of suite package.
+It exits with 0 if all scenarios pass, 1 if any of them fails and 2 if
+the suite is not executed.
+The JSON output of the run is convertible to HTML report offline
+
+  assay -format json ./suites > run.jsonl
+  assay report -o report.html run.jsonl
+
+The skeleton of suite is generated from OpenAPI 3 document, one scenario
+per operation
+
+  assay generate -o suites/api.go -package suites openapi.yaml
+
+The command is the own module, install it from the checkout of SDK
+
+  cd cmd/assay && go install .
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/assay-it/sdk-go/suite"
+)
+
+var runner = template.Must(template.New("main").Parse(`// Code generated by assay. DO NOT EDIT.
+
+package main
+
+import (
+	_ "{{.}}"
+
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func main() {
+	suite.Main()
+}
+`))
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "report":
+			os.Exit(report(os.Args[2:]))
+		case "generate":
+			os.Exit(generate(os.Args[2:]))
+		}
+	}
+
+	var options suite.Options
+	options.Flags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: assay [flags] package\n       assay report [-o file] run.jsonl\n       assay generate [-o file] [-package name] openapi.yaml\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	code, err := run(flag.Arg(0), forward())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+// forward returns command line flags to be passed to the runner
+func forward() []string {
+	args := []string{}
+	flag.Visit(func(f *flag.Flag) {
+		args = append(args, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+	})
+	return args
+}
+
+func run(pkg string, args []string) (int, error) {
+	path, err := importPath(pkg)
+	if err != nil {
+		return 2, err
+	}
+
+	dir, err := ioutil.TempDir(".", ".assay-")
+	if err != nil {
+		return 2, err
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	if err := runner.Execute(&src, path); err != nil {
+		return 2, err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0644); err != nil {
+		return 2, err
+	}
+
+	// Note: the runner is built and executed by the command, go run
+	//       reports any failure of program with exit status 1.
+	bin := filepath.Join(dir, "suite")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+
+	build := exec.Command("go", "build", "-o", bin, "./"+filepath.ToSlash(dir))
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err := build.Run(); err != nil {
+		return 2, fmt.Errorf("unable to build suite %s: %v", pkg, err)
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return exit.ExitCode(), nil
+		}
+		return 2, err
+	}
+	return 0, nil
+}
+
+// importPath resolves relative path of the package to import path
+func importPath(pkg string) (string, error) {
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", pkg).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("unable to resolve package %s: %s", pkg, exit.Stderr)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go toolchain is required")
+	}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain is not found")
+	}
+
+	pkg := fixture(t)
+	defer os.Remove("testdata")
+	defer os.RemoveAll(pkg)
+
+	for expect, args := range map[int][]string{
+		0: {"-run=Pass"},
+		1: {"-run=Fail"},
+		2: {"-format=unknown"},
+	} {
+		code, err := run("./"+filepath.ToSlash(pkg), args)
+		if err != nil || code != expect {
+			t.Errorf("unexpected exit code %d (%v) of %v, expected %d", code, err, args, expect)
+		}
+	}
+}
+
+func TestRunUnknownPackage(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain is not found")
+	}
+
+	if code, err := run("./testdata/none", nil); err == nil || code != 2 {
+		t.Errorf("unknown package is executed: %d (%v)", code, err)
+	}
+}
+
+// fixture writes the suite with passing and failing scenarios into the module
+func fixture(t *testing.T) string {
+	t.Helper()
+
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("testdata", "suite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := `package suite
+
+import (
+	"errors"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func init() {
+	suite.Register("Pass", func() assay.Arrow {
+		return func(cat *assay.IOCat) *assay.IOCat { return cat }
+	})
+	suite.Register("Fail", func() assay.Arrow {
+		return func(cat *assay.IOCat) *assay.IOCat {
+			cat.Fail = errors.New("fail")
+			return cat
+		}
+	})
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "suite.go"), []byte(src), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return dir
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/assay-it/sdk-go/suite"
+)
+
+// report renders HTML report from the recorded run file
+func report(args []string) int {
+	fs := flag.NewFlagSet("assay report", flag.ContinueOnError)
+	output := fs.String("o", "", "output file of HTML report, default stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: assay report [-o file] run.jsonl\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := render(fs.Arg(0), *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func render(input, output string) error {
+	r, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	seq, err := suite.ReadRecords(r)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return suite.WriteHTML(w, seq)
+}
@@ -0,0 +1,8 @@
+module github.com/assay-it/sdk-go
+
+go 1.22
+
+require (
+	github.com/ajg/form v1.5.1
+	github.com/google/go-cmp v0.5.9
+)
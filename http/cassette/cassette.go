@@ -0,0 +1,397 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package cassette records HTTP traffic of suites to the file and replays it
+without network, so that suites are executed offline and deterministically.
+The cassette is compatible with http.Stack
+
+  // record traffic
+  c := cassette.Record("testdata/users.json")
+  defer c.Save()
+  cat := assay.IO(http.Stack(c.Client()))
+
+  // replay traffic
+  c, err := cassette.Replay("testdata/users.json", cassette.MatchHeader("Accept"))
+  cat := assay.IO(http.Stack(c.Client()))
+
+The request is matched with recorded one by method, URL, selected headers
+and body. Sensitive data is redacted with assay.DefaultRedactions before
+it is stored to the cassette, the replayed request is redacted with same
+rules before it is matched.
+*/
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+/*
+
+Interaction is recorded pair of request and response
+*/
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is recorded HTTP request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is recorded HTTP response
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+/*
+
+Cassette is http.RoundTripper, which either records or replays traffic
+*/
+type Cassette struct {
+	sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+
+	path       string
+	replay     bool
+	used       []bool
+	headers    []string
+	transport  http.RoundTripper
+	redactions []assay.Redaction
+}
+
+/*
+
+Option configures the cassette
+*/
+type Option func(*Cassette)
+
+/*
+
+MatchHeader defines headers to match requests with recorded ones in
+addition to method, URL and body.
+*/
+func MatchHeader(headers ...string) Option {
+	return func(c *Cassette) {
+		c.headers = append(c.headers, headers...)
+	}
+}
+
+/*
+
+Transport defines the transport used to record traffic, default one is
+http.DefaultTransport.
+*/
+func Transport(transport http.RoundTripper) Option {
+	return func(c *Cassette) {
+		c.transport = transport
+	}
+}
+
+/*
+
+Redact defines rules to hide sensitive data before it is recorded, they
+replace assay.DefaultRedactions. The rules are applied to the request before
+it is matched with recorded ones, so that redacted values are not compared.
+*/
+func Redact(rules ...assay.Redaction) Option {
+	return func(c *Cassette) {
+		c.redactions = rules
+	}
+}
+
+/*
+
+Record creates the cassette, which sends requests to network and records
+exchanges. Use Save to store recorded exchanges to the file.
+*/
+func Record(path string, opts ...Option) *Cassette {
+	c := &Cassette{
+		path:       path,
+		transport:  http.DefaultTransport,
+		redactions: assay.DefaultRedactions(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+/*
+
+Replay loads the cassette from the file. The cassette serves recorded
+responses without network, the request fails with Mismatch error if it
+does not match any recorded one.
+*/
+func Replay(path string, opts ...Option) (*Cassette, error) {
+	c := Record(path, opts...)
+	c.replay = true
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load cassette: %w", err)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+
+	return c, nil
+}
+
+/*
+
+Client creates HTTP client for http.Stack, the client does not follow
+redirects like http.Default stack.
+*/
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{
+		Transport: c,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+/*
+
+Save stores recorded exchanges to the file
+*/
+func (c *Cassette) Save() error {
+	c.Lock()
+	defer c.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data, 0644)
+}
+
+/*
+
+RoundTrip either replays or records the exchange
+*/
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.replay {
+		return c.play(req, body)
+	}
+	return c.record(req, body)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	in, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := ioutil.ReadAll(in.Body)
+	in.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	in.Body = ioutil.NopCloser(bytes.NewReader(payload))
+
+	ig := &assay.Event{Type: assay.EventIngress, URL: req.URL, Header: in.Header.Clone(), Body: payload}
+	for _, rule := range c.redactions {
+		rule(ig)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.Interactions = append(c.Interactions, Interaction{
+		Request: c.request(req, body),
+		Response: Response{
+			Status: in.StatusCode,
+			Header: ig.Header,
+			Body:   string(ig.Body),
+		},
+	})
+
+	return in, nil
+}
+
+// request captures the request as it is recorded, sensitive data is redacted
+func (c *Cassette) request(req *http.Request, body []byte) Request {
+	eg := &assay.Event{Type: assay.EventEgress, URL: req.URL, Header: req.Header.Clone(), Body: body}
+	for _, rule := range c.redactions {
+		rule(eg)
+	}
+
+	return Request{
+		Method: req.Method,
+		URL:    eg.URL.String(),
+		Header: eg.Header,
+		Body:   string(eg.Body),
+	}
+}
+
+func (c *Cassette) play(req *http.Request, body []byte) (*http.Response, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	live := c.request(req, body)
+
+	// unused interactions are replayed in the order of recording, the last
+	// used one is replayed again if the request is repeated more times than
+	// it was recorded.
+	found, closest, diff := -1, -1, ""
+	for i := len(c.Interactions) - 1; i >= 0; i-- {
+		d := c.diff(c.Interactions[i].Request, live)
+		switch {
+		case d == "" && (found == -1 || !c.used[i]):
+			found = i
+		case d != "" && (closest == -1 || len(d) <= len(diff)):
+			closest, diff = i, d
+		}
+	}
+
+	if found == -1 {
+		err := &Mismatch{Method: live.Method, URL: live.URL}
+		if closest != -1 {
+			err.Closest, err.Diff = &c.Interactions[closest].Request, diff
+		}
+		return nil, err
+	}
+
+	c.used[found] = true
+	rec := c.Interactions[found].Response
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// diff explains the difference between recorded and actual request, both
+// requests are redacted
+func (c *Cassette) diff(rec, req Request) string {
+	seq := []string{}
+
+	if rec.Method != req.Method {
+		seq = append(seq, fmt.Sprintf("method %s, recorded %s", req.Method, rec.Method))
+	}
+
+	if !sameURL(rec.URL, req.URL) {
+		seq = append(seq, fmt.Sprintf("url %s, recorded %s", req.URL, rec.URL))
+	}
+
+	for _, head := range c.headers {
+		if actual, expect := req.Header.Get(head), rec.Header.Get(head); actual != expect {
+			seq = append(seq, fmt.Sprintf("header %s: %q, recorded %q", head, actual, expect))
+		}
+	}
+
+	if !sameBody([]byte(rec.Body), []byte(req.Body)) {
+		seq = append(seq, fmt.Sprintf("body %s, recorded %s", req.Body, rec.Body))
+	}
+
+	return strings.Join(seq, "; ")
+}
+
+// sameURL compares URLs ignoring order of query params
+func sameURL(rec, req string) bool {
+	a, err := url.Parse(rec)
+	if err != nil {
+		return false
+	}
+
+	b, err := url.Parse(req)
+	if err != nil {
+		return false
+	}
+
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path &&
+		reflect.DeepEqual(a.Query(), b.Query())
+}
+
+// sameBody compares JSON documents semantically, other content byte by byte
+func sameBody(rec, req []byte) bool {
+	if bytes.Equal(rec, req) {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal(rec, &a) != nil || json.Unmarshal(req, &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+/*
+
+Mismatch is returned if the request does not match any recorded one
+*/
+type Mismatch struct {
+	Method  string
+	URL     string
+	Closest *Request
+	Diff    string
+}
+
+func (e *Mismatch) Error() string {
+	if e.Closest == nil {
+		return fmt.Sprintf("cassette: %s %s is not recorded", e.Method, e.URL)
+	}
+	return fmt.Sprintf("cassette: %s %s is not recorded, closest %s %s differs by %s",
+		e.Method, e.URL, e.Closest.Method, e.Closest.URL, e.Diff)
+}
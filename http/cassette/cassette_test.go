@@ -0,0 +1,157 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package cassette_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/http/cassette"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func create(host string, user *User) assay.Arrow {
+	return µ.Join(
+		ø.POST(host + "/users"),
+		ø.ContentJSON(),
+		ø.AcceptJSON(),
+		ø.Authorization().Is("Bearer secret"),
+		ø.Send(User{Name: "a"}),
+		ƒ.Code(µ.StatusCreated),
+		ƒ.Recv(user),
+	)
+}
+
+func TestRecordReplay(t *testing.T) {
+	ts := mock()
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	rec := cassette.Record(path)
+	var user User
+	if cat := create(ts.URL, &user)(assay.IO(µ.Stack(rec.Client()))); cat.Fail != nil || user.ID != "1" {
+		t.Fatalf("unable to record: %v", cat.Fail)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+
+	data, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(data), "Bearer secret") {
+		t.Errorf("secret is recorded %s", data)
+	}
+
+	play, err := cassette.Replay(path, cassette.MatchHeader("Accept"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		user = User{}
+		if cat := create(ts.URL, &user)(assay.IO(µ.Stack(play.Client()))); cat.Fail != nil || user.ID != "1" {
+			t.Errorf("unable to replay: %v", cat.Fail)
+		}
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "users.json")
+
+	rec := cassette.Record(path)
+	create(ts.URL, &User{})(assay.IO(µ.Stack(rec.Client())))
+	rec.Save()
+
+	play, err := cassette.Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := µ.Join(
+		ø.POST(ts.URL + "/users"),
+		ø.ContentJSON(),
+		ø.Send(User{Name: "b"}),
+		ƒ.Code(µ.StatusCreated),
+	)
+
+	cat := f(assay.IO(µ.Stack(play.Client())))
+
+	var mismatch *cassette.Mismatch
+	if !errors.As(cat.Fail, &mismatch) || mismatch.Closest == nil ||
+		!strings.Contains(mismatch.Error(), `body {"id":"","name":"b"}`) {
+		t.Errorf("unexpected failure %v", cat.Fail)
+	}
+}
+
+func TestReplayRedacted(t *testing.T) {
+	ts := mock()
+	path := filepath.Join(t.TempDir(), "login.json")
+
+	login := µ.Join(
+		ø.POST(ts.URL+"/login?access_token=xxx"),
+		ø.ContentJSON(),
+		ø.Authorization().Is("Bearer xxx"),
+		ø.Send(map[string]string{"user": "a", "password": "xxx"}),
+		ƒ.Code(µ.StatusOK),
+	)
+
+	rec := cassette.Record(path)
+	if cat := login(assay.IO(µ.Stack(rec.Client()))); cat.Fail != nil {
+		t.Fatalf("unable to record: %v", cat.Fail)
+	}
+	rec.Save()
+	ts.Close()
+
+	play, err := cassette.Replay(path, cassette.MatchHeader("Authorization"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cat := login(assay.IO(µ.Stack(play.Client()))); cat.Fail != nil {
+		t.Errorf("unable to replay redacted request: %v", cat.Fail)
+	}
+}
+
+func TestReplayInvalid(t *testing.T) {
+	if _, err := cassette.Replay(filepath.Join(t.TempDir(), "none.json")); err == nil {
+		t.Error("missing cassette is loaded")
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/users" && r.Method == http.MethodPost:
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"1","name":"a"}`))
+			case r.URL.Path == "/login":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+}
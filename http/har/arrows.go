@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package har
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+/*
+
+Option customizes arrows built from HAR entries
+*/
+type Option func(*options)
+
+type options struct {
+	host   *url.URL
+	filter func(Entry) bool
+}
+
+/*
+
+Host replaces scheme and host of recorded requests with the target, e.g.
+to run exchanges recorded in production against staging.
+*/
+func Host(target string) Option {
+	return func(opts *options) {
+		if addr, err := url.Parse(target); err == nil {
+			opts.host = addr
+		}
+	}
+}
+
+/*
+
+Filter selects entries to be evaluated, e.g. to skip static assets
+*/
+func Filter(f func(Entry) bool) Option {
+	return func(opts *options) {
+		opts.filter = f
+	}
+}
+
+// skip headers managed by HTTP stack or bound to the browser session
+var skip = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"cookie":            true,
+	"transfer-encoding": true,
+	"keep-alive":        true,
+	"upgrade":           true,
+}
+
+/*
+
+Arrow builds send/recv arrows of the entry. The request is sent with
+recorded method, URL, headers and content. The recorded status is the
+expectation of recv.Code.
+*/
+func (e Entry) Arrow(opts ...Option) assay.Arrow {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return e.arrow(o)
+}
+
+func (e Entry) arrow(o options) assay.Arrow {
+	addr := e.Request.URL
+	if o.host != nil {
+		if u, err := url.Parse(addr); err == nil {
+			u.Scheme, u.Host = o.host.Scheme, o.host.Host
+			addr = u.String()
+		}
+	}
+
+	seq := []http.Arrow{ø.URL(e.Request.Method, "!%s", addr)}
+
+	content := false
+	for _, h := range e.Request.Headers {
+		name := strings.ToLower(h.Name)
+		if strings.HasPrefix(name, ":") || skip[name] {
+			continue
+		}
+		content = content || name == "content-type"
+		seq = append(seq, ø.Header(h.Name).Is(h.Value))
+	}
+
+	if e.Request.PostData != nil && e.Request.PostData.Text != "" {
+		if !content {
+			seq = append(seq, ø.Content().Is(e.Request.PostData.MimeType))
+		}
+		seq = append(seq, ø.Send(e.Request.PostData.Text))
+	}
+
+	// status is not known if browser has aborted the request
+	if e.Response.Status > 0 {
+		seq = append(seq, ƒ.Code(http.NewStatusCode(e.Response.Status)))
+	}
+
+	return http.Join(seq...)
+}
+
+/*
+
+Scenario builds arrows of all entries, they are evaluated sequentially in
+the order of recording.
+*/
+func (h *HAR) Scenario(opts ...Option) assay.Arrow {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	seq := []assay.Arrow{}
+	for _, e := range h.Log.Entries {
+		if o.filter == nil || o.filter(e) {
+			seq = append(seq, assay.Label(e.Request.Method+" "+e.Request.URL, e.arrow(o)))
+		}
+	}
+	return assay.Join(seq...)
+}
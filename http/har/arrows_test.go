@@ -0,0 +1,103 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package har_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/http/har"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+func TestImport(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	rec := har.NewRecorder()
+	f := µ.Join(
+		ø.POST(ts.URL+"/users"),
+		ø.ContentJSON(),
+		ø.Send(map[string]string{"name": "a"}),
+		ƒ.Code(µ.StatusCreated),
+	)
+	f(assay.IO(µ.Default(), assay.Observe(rec)))
+
+	buf := &bytes.Buffer{}
+	if err := rec.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := har.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := mock()
+	defer other.Close()
+
+	if cat := h.Scenario(har.Host(other.URL))(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Errorf("unable to replay HAR: %v", cat.Fail)
+	}
+}
+
+func TestImportDevtools(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	h, err := har.Read(strings.NewReader(`{"log": {"version": "1.2", "entries": [
+		{
+			"request": {
+				"method": "GET",
+				"url": "https://example.com/users/1",
+				"headers": [
+					{"name": ":authority", "value": "example.com"},
+					{"name": "accept", "value": "application/json"},
+					{"name": "cookie", "value": "session=a"}
+				]
+			},
+			"response": {"status": 200}
+		},
+		{
+			"request": {"method": "GET", "url": "https://example.com/style.css", "headers": []},
+			"response": {"status": 200}
+		},
+		{
+			"request": {"method": "GET", "url": "https://example.com/users/2", "headers": []},
+			"response": {"status": 200}
+		}
+	]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := h.Scenario(
+		har.Host(ts.URL),
+		har.Filter(func(e har.Entry) bool { return !strings.HasSuffix(e.Request.URL, ".css") }),
+	)
+
+	cat := f(assay.IO(µ.Default(), assay.Tracing()))
+	if cat.Fail == nil {
+		t.Fatal("unexpected success")
+	}
+
+	failed := []string{}
+	for _, step := range cat.Trace() {
+		if step.Depth == 0 && step.Fail != nil {
+			failed = append(failed, step.Label)
+		}
+	}
+	if len(failed) != 1 || failed[0] != "GET https://example.com/users/2" {
+		t.Errorf("unexpected failed steps %v", failed)
+	}
+}
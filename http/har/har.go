@@ -0,0 +1,330 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package har exports HTTP exchanges of suites to HAR 1.2 files and imports
+HAR files (e.g. exported from browser devtools) as runnable scenarios.
+
+Use Recorder to observe exchanges evaluated by the category
+
+  rec := har.NewRecorder()
+  cat := assay.IO(http.Default(), assay.Observe(rec))
+  ...
+  rec.Save("suite.har")
+
+Use Load to replay exchanges as send/recv arrows, captured statuses become
+recv.Code expectations
+
+  h, err := har.Load("devtools.har")
+  cat = h.Scenario(har.Host("https://staging.example.com"))(cat)
+
+*/
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+// HAR is the root of HTTP Archive document
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log of HTTP exchanges
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator of the log
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is HTTP exchange
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+}
+
+// Request of HTTP exchange
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Response of HTTP exchange
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+	Error       string      `json:"_error,omitempty"`
+}
+
+// NameValue is a header or query param
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Cookie of request or response
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the content of request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content of response
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings of HTTP exchange in milliseconds, -1 if timing is not known
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+/*
+
+Read decodes HAR document
+*/
+func Read(r io.Reader) (*HAR, error) {
+	var h HAR
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		return nil, fmt.Errorf("invalid HAR: %w", err)
+	}
+	return &h, nil
+}
+
+/*
+
+Load reads HAR document from the file
+*/
+func Load(path string) (*HAR, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+/*
+
+Recorder is assay.Logger, which observes I/O traffic of category and builds
+HAR document from it. Install it with assay.Observe config.
+*/
+type Recorder struct {
+	sync.Mutex
+	entries []Entry
+	pending []*assay.Event
+	started []time.Time
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+/*
+
+Log records the event, the egress event is paired with subsequent ingress
+event of the same exchange.
+*/
+func (r *Recorder) Log(e *assay.Event) {
+	r.Lock()
+	defer r.Unlock()
+
+	switch e.Type {
+	case assay.EventEgress:
+		r.pending = append(r.pending, e)
+		r.started = append(r.started, time.Now())
+	case assay.EventIngress:
+		for i, eg := range r.pending {
+			if eg.Method == e.Method && eg.URL.String() == e.URL.String() {
+				r.pending = append(r.pending[:i], r.pending[i+1:]...)
+				r.started = append(r.started[:i], r.started[i+1:]...)
+				r.entries = append(r.entries, entry(eg, e))
+				return
+			}
+		}
+	}
+}
+
+/*
+
+HAR returns the document of recorded exchanges. Requests without response
+(e.g. connection errors or cancellations) are listed with status 0 and
+_error. Take the document once the suite is completed, otherwise requests
+in flight are listed as failed.
+*/
+func (r *Recorder) HAR() *HAR {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, eg := range r.pending {
+		r.entries = append(r.entries, failed(eg, r.started[i]))
+	}
+	r.pending, r.started = nil, nil
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+
+	return &HAR{
+		Log: Log{
+			Version: "1.2",
+			Creator: Creator{Name: "assay-it/sdk-go", Version: "1.0"},
+			Entries: entries,
+		},
+	}
+}
+
+/*
+
+Write encodes the document of recorded exchanges
+*/
+func (r *Recorder) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.HAR())
+}
+
+/*
+
+Save writes the document of recorded exchanges to the file
+*/
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.HAR(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// entry builds HAR entry from egress and ingress events
+func entry(eg, in *assay.Event) Entry {
+	ms := float64(in.Duration) / float64(time.Millisecond)
+	started := time.Now().Add(-in.Duration)
+
+	return Entry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request:         request(eg),
+		Response: Response{
+			Status:      in.Status,
+			StatusText:  http.StatusText(in.Status),
+			HTTPVersion: in.Proto,
+			Cookies:     []Cookie{},
+			Headers:     pairs(in.Header),
+			Content: Content{
+				Size:     int64(len(in.Body)),
+				MimeType: in.Header.Get("Content-Type"),
+				Text:     string(in.Body),
+			},
+			HeadersSize: -1,
+			BodySize:    in.Size,
+		},
+		Timings: Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: ms},
+	}
+}
+
+// failed builds HAR entry from egress event, which has no response
+func failed(eg *assay.Event, started time.Time) Entry {
+	return Entry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request:         request(eg),
+		Response: Response{
+			Cookies:     []Cookie{},
+			Headers:     []NameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+			Error:       "response is not received",
+		},
+		Timings: Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+	}
+}
+
+func request(eg *assay.Event) Request {
+	req := Request{
+		Method:      eg.Method,
+		URL:         eg.URL.String(),
+		HTTPVersion: eg.Proto,
+		Cookies:     []Cookie{},
+		Headers:     pairs(eg.Header),
+		QueryString: pairs(eg.URL.Query()),
+		HeadersSize: -1,
+		BodySize:    eg.Size,
+	}
+
+	if len(eg.Body) > 0 {
+		req.PostData = &PostData{MimeType: eg.Header.Get("Content-Type"), Text: string(eg.Body)}
+	}
+	return req
+}
+
+// pairs converts headers or query params to sorted name/value pairs
+func pairs(h map[string][]string) []NameValue {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seq := []NameValue{}
+	for _, key := range keys {
+		for _, val := range h[key] {
+			seq = append(seq, NameValue{key, val})
+		}
+	}
+	return seq
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package har_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/http/har"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+func TestRecorder(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	rec := har.NewRecorder()
+	f := assay.Join(
+		µ.Join(
+			ø.POST(ts.URL+"/users?a=b"),
+			ø.ContentJSON(),
+			ø.Send(map[string]string{"name": "a"}),
+			ƒ.Code(µ.StatusCreated),
+		),
+		µ.Join(
+			ø.GET(ts.URL+"/users/1"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	if cat := f(assay.IO(µ.Default(), assay.Observe(rec))); cat.Fail != nil {
+		t.Fatal(cat.Fail)
+	}
+
+	h := rec.HAR()
+	if h.Log.Version != "1.2" || len(h.Log.Entries) != 2 {
+		t.Fatalf("unexpected HAR %+v", h)
+	}
+
+	post := h.Log.Entries[0]
+	if post.Request.Method != "POST" || post.Request.PostData == nil ||
+		post.Request.PostData.Text != `{"name":"a"}` ||
+		len(post.Request.QueryString) != 1 ||
+		post.Response.Status != 201 ||
+		post.Response.Content.Text != `{"id":"1"}` {
+		t.Errorf("unexpected entry %+v", post)
+	}
+}
+
+func TestRecorderFailed(t *testing.T) {
+	ts := mock()
+	ts.Close()
+
+	rec := har.NewRecorder()
+	f := µ.Join(
+		ø.GET(ts.URL+"/users/1"),
+		ƒ.Code(µ.StatusOK),
+	)
+
+	if cat := f(assay.IO(µ.Default(), assay.Observe(rec))); cat.Fail == nil {
+		t.Fatal("request to closed server is succeeded")
+	}
+
+	h := rec.HAR()
+	if len(h.Log.Entries) != 1 {
+		t.Fatalf("unexpected HAR %+v", h)
+	}
+
+	get := h.Log.Entries[0]
+	if get.Request.Method != "GET" || get.Response.Status != 0 || get.Response.Error == "" {
+		t.Errorf("unexpected entry %+v", get)
+	}
+
+	if h = rec.HAR(); len(h.Log.Entries) != 1 {
+		t.Errorf("failed request is recorded twice %+v", h)
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/users" && r.Method == http.MethodPost:
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"1"}`))
+			case r.URL.Path == "/users/1" && r.Header.Get("Cookie") == "":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+}
@@ -0,0 +1,384 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package mock implements in-process fake upstream for testing Behavior as a
+Code suites. Routes are declared with expectations about requests and canned
+responses, the server verifies that all expectations are met.
+
+  ts := mock.New(
+    mock.On("GET", "/users/{id}").
+      Header("Accept", "application/json").
+      Reply(http.StatusOK, User{ID: "1"}),
+    mock.On("POST", "/users").
+      Body(User{Name: "a"}).
+      Times(1).
+      Reply(http.StatusCreated),
+  )
+
+Expectations are arrows of recv and cats packages evaluated against the
+request as it would be received, so that any other arrow is usable as well
+
+  mock.On("POST", "/users").
+    Match(ƒ.Header("Content-Type").Is("application/json")).
+    Reply(http.StatusCreated)
+
+  defer ts.Close()
+  ...
+  if err := ts.Verify(); err != nil {
+    t.Error(err)
+  }
+
+*/
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/cats"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+/*
+
+Expect is the route of mock server, it defines expectations about request
+and canned response.
+*/
+type Expect struct {
+	method  string
+	pattern []string
+	arrows  []µ.Arrow
+	body    µ.Arrow
+	times   int
+
+	status  int
+	content interface{}
+	reply   http.Header
+
+	mu    sync.Mutex
+	count int
+}
+
+/*
+
+On declares the route for the method and the path pattern. The pattern
+segment {name} matches any segment, the trailing * matches any suffix.
+  mock.On("GET", "/users/{id}")
+  mock.On("GET", "/static/*")
+*/
+func On(method, pattern string) *Expect {
+	return &Expect{
+		method:  method,
+		pattern: segments(pattern),
+		status:  http.StatusOK,
+		reply:   http.Header{},
+	}
+}
+
+/*
+
+Match expects the request to satisfy arrows of recv and cats packages. The
+request is seen by arrows as the received response, arrows which consume
+the content (e.g. recv.Recv) shall be last ones.
+*/
+func (e *Expect) Match(arrows ...µ.Arrow) *Expect {
+	e.arrows = append(e.arrows, arrows...)
+	return e
+}
+
+// Header expects the request header, see recv.Header
+func (e *Expect) Header(name, value string) *Expect {
+	return e.Match(ƒ.Header(name).Is(value))
+}
+
+// Query expects the query param of request
+func (e *Expect) Query(name, value string) *Expect {
+	return e.Match(func(cat *assay.IOCat) *assay.IOCat {
+		actual := cat.HTTP.Recv.Request.URL.Query().Get(name)
+		return cats.Value(&actual).String(value)(cat)
+	})
+}
+
+/*
+
+Body expects content of request. The content is decoded with recv.Recv into
+the type of expected value and compared with it. Use nil to expect empty
+content.
+*/
+func (e *Expect) Body(body interface{}) *Expect {
+	if body == nil {
+		var data []byte
+		e.body = µ.Arrow(µ.Join(
+			ƒ.Bytes(&data),
+			func(cat *assay.IOCat) *assay.IOCat {
+				actual := string(data)
+				return cats.Value(&actual).String("")(cat)
+			},
+		))
+		return e
+	}
+
+	actual := reflect.New(reflect.TypeOf(body))
+	e.body = µ.Arrow(µ.Join(
+		ƒ.Recv(actual.Interface()),
+		func(cat *assay.IOCat) *assay.IOCat {
+			return cats.Value(actual.Elem().Interface()).Is(body)(cat)
+		},
+	))
+	return e
+}
+
+/*
+
+Times expects the route to be requested exactly n times. The route stops
+matching requests once it is requested n times. By default, the route is
+expected at least once.
+*/
+func (e *Expect) Times(n int) *Expect {
+	e.times = n
+	return e
+}
+
+/*
+
+Reply defines the canned response. The content is either string, bytes or
+value encoded with send.Send using Content-Type of response (JSON unless it
+is defined with With).
+*/
+func (e *Expect) Reply(status int, content ...interface{}) *Expect {
+	e.status = status
+	if len(content) == 0 {
+		return e
+	}
+
+	e.content = content[0]
+	switch e.content.(type) {
+	case string, []byte:
+	default:
+		if e.reply.Get("Content-Type") == "" {
+			e.reply.Set("Content-Type", "application/json")
+		}
+	}
+	return e
+}
+
+// With defines the header of canned response
+func (e *Expect) With(name, value string) *Expect {
+	e.reply.Set(name, value)
+	return e
+}
+
+// Count returns number of requests matched by the route
+func (e *Expect) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.count
+}
+
+func (e *Expect) String() string {
+	return fmt.Sprintf("%s /%s", e.method, strings.Join(e.pattern, "/"))
+}
+
+// match explains the difference between the request and expectation
+func (e *Expect) match(r *http.Request, body []byte) string {
+	if r.Method != e.method {
+		return fmt.Sprintf("method %s, expected %s", r.Method, e.method)
+	}
+
+	if !e.path(segments(r.URL.Path)) {
+		return fmt.Sprintf("path %s, expected %s", r.URL.Path, e)
+	}
+
+	arrows := e.arrows
+	if e.body != nil {
+		arrows = append(append([]µ.Arrow{}, e.arrows...), e.body)
+	}
+
+	cat := assay.IO()
+	cat.HTTP = &assay.IOCatHTTP{
+		Recv: &assay.DnStreamHTTP{
+			Response: &http.Response{
+				Header:        r.Header,
+				Body:          ioutil.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       r,
+			},
+		},
+	}
+
+	if cat = µ.Join(arrows...)(cat); cat.Fail != nil {
+		return cat.Fail.Error()
+	}
+	return ""
+}
+
+// payload encodes the content of canned response
+func (e *Expect) payload() ([]byte, error) {
+	if e.content == nil {
+		return nil, nil
+	}
+
+	content := e.reply.Get("Content-Type")
+	cat := assay.IO()
+	cat.HTTP = &assay.IOCatHTTP{
+		Send: &assay.UpStreamHTTP{Header: map[string]*string{"content-type": &content}},
+	}
+
+	if cat = ø.Send(e.content)(cat); cat.Fail != nil {
+		return nil, cat.Fail
+	}
+	return ioutil.ReadAll(cat.HTTP.Send.Payload)
+}
+
+func (e *Expect) path(seq []string) bool {
+	for i, p := range e.pattern {
+		switch {
+		case i >= len(seq):
+			return false
+		case p == "*" && i == len(e.pattern)-1:
+			return true
+		case strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}"):
+			continue
+		case p != seq[i]:
+			return false
+		}
+	}
+	return len(seq) == len(e.pattern)
+}
+
+// take counts the request if route still accepts requests
+func (e *Expect) take() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.times > 0 && e.count >= e.times {
+		return false
+	}
+	e.count++
+	return true
+}
+
+func segments(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+/*
+
+Server is mock upstream
+*/
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	expects    []*Expect
+	unexpected []string
+}
+
+/*
+
+New starts the mock server with declared routes. Routes are matched in the
+order of declaration.
+*/
+func New(expects ...*Expect) *Server {
+	s := &Server{expects: expects}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	diff := []string{}
+	for _, e := range s.expects {
+		d := e.match(r, body)
+		if d == "" && e.take() {
+			s.reply(w, e)
+			return
+		}
+
+		if d == "" {
+			d = fmt.Sprintf("requested more than %d times", e.times)
+		}
+		diff = append(diff, fmt.Sprintf("%s: %s", e, d))
+	}
+
+	msg := fmt.Sprintf("unexpected %s %s", r.Method, r.URL)
+	if len(diff) > 0 {
+		msg = fmt.Sprintf("%s\n%s", msg, strings.Join(diff, "\n"))
+	}
+
+	s.mu.Lock()
+	s.unexpected = append(s.unexpected, msg)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNotImplemented)
+	w.Write([]byte(msg))
+}
+
+func (s *Server) reply(w http.ResponseWriter, e *Expect) {
+	payload, err := e.payload()
+	if err != nil {
+		msg := fmt.Sprintf("%s: invalid reply: %v", e, err)
+		s.mu.Lock()
+		s.unexpected = append(s.unexpected, msg)
+		s.mu.Unlock()
+
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(msg))
+		return
+	}
+
+	for name, vals := range e.reply {
+		w.Header()[name] = vals
+	}
+	w.WriteHeader(e.status)
+	w.Write(payload)
+}
+
+/*
+
+Verify checks that all routes are requested expected number of times and
+the server has not received unexpected requests. It fails with Mismatch
+error, which lists violations.
+*/
+func (s *Server) Verify() error {
+	seq := []string{}
+	for _, e := range s.expects {
+		switch n := e.Count(); {
+		case e.times > 0 && n != e.times:
+			seq = append(seq, fmt.Sprintf("%s: requested %d times, expected %d", e, n, e.times))
+		case n == 0:
+			seq = append(seq, fmt.Sprintf("%s: not requested", e))
+		}
+	}
+
+	s.mu.Lock()
+	seq = append(seq, s.unexpected...)
+	s.mu.Unlock()
+
+	if len(seq) == 0 {
+		return nil
+	}
+
+	return &assay.Mismatch{Diff: strings.Join(seq, "\n")}
+}
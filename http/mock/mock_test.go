@@ -0,0 +1,209 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package mock_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/cats"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/http/mock"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+type User struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestMock(t *testing.T) {
+	lookup := mock.On("GET", "/users/{id}").
+		Header("Accept", "application/json").
+		Query("fields", "name").
+		Reply(http.StatusOK, User{ID: "1", Name: "a"})
+
+	create := mock.On("POST", "/users").
+		Body(User{Name: "a"}).
+		Times(1).
+		Reply(http.StatusCreated).
+		With("Location", "/users/1")
+
+	ts := mock.New(lookup, create, mock.On("GET", "/static/*").Reply(http.StatusOK, "ok"))
+	defer ts.Close()
+
+	var user User
+	var static []byte
+	f := assay.Join(
+		µ.Join(
+			ø.POST(ts.URL+"/users"),
+			ø.ContentJSON(),
+			ø.Send(User{Name: "a"}),
+			ƒ.Code(µ.StatusCreated),
+			ƒ.Header("Location").Is("/users/1"),
+		),
+		µ.Join(
+			ø.GET(ts.URL+"/users/1?fields=name"),
+			ø.AcceptJSON(),
+			ƒ.Code(µ.StatusOK),
+			ƒ.ServedJSON(),
+			ƒ.Recv(&user),
+		),
+		µ.Join(
+			ø.GET(ts.URL+"/static/a/b.css"),
+			ƒ.Code(µ.StatusOK),
+			ƒ.Bytes(&static),
+		),
+	)
+
+	if cat := f(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Fatal(cat.Fail)
+	}
+
+	if user.ID != "1" || string(static) != "ok" {
+		t.Errorf("unexpected response %v %s", user, static)
+	}
+
+	if err := ts.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	if lookup.Count() != 1 || create.Count() != 1 {
+		t.Errorf("unexpected counts %d %d", lookup.Count(), create.Count())
+	}
+}
+
+func TestMockUnexpected(t *testing.T) {
+	ts := mock.New(
+		mock.On("POST", "/users").Body(User{Name: "a"}).Times(1).Reply(http.StatusCreated),
+		mock.On("GET", "/users/{id}").Reply(http.StatusOK),
+	)
+	defer ts.Close()
+
+	f := µ.Join(
+		ø.POST(ts.URL+"/users"),
+		ø.ContentJSON(),
+		ø.Send(User{Name: "b"}),
+		ƒ.Code(µ.StatusCreated),
+	)
+	f(assay.IO(µ.Default()))
+
+	var mismatch *assay.Mismatch
+	err := ts.Verify()
+	if !errors.As(err, &mismatch) ||
+		!strings.Contains(mismatch.Diff, "POST /users: requested 0 times, expected 1") ||
+		!strings.Contains(mismatch.Diff, "GET /users/{id}: not requested") ||
+		!strings.Contains(mismatch.Diff, "unexpected POST /users") ||
+		!strings.Contains(mismatch.Diff, `Name: "b"`) {
+		t.Errorf("unexpected verification %v", err)
+	}
+}
+
+func TestMockMatch(t *testing.T) {
+	var user User
+	ts := mock.New(
+		mock.On("POST", "/users").
+			Match(
+				ƒ.ServedJSON(),
+				ƒ.Recv(&user),
+				µ.Arrow(cats.Value(&user.Name).String("a")),
+			).
+			Reply(http.StatusCreated),
+	)
+	defer ts.Close()
+
+	send := func(name string) assay.Arrow {
+		return µ.Join(
+			ø.POST(ts.URL+"/users"),
+			ø.ContentJSON(),
+			ø.Send(User{Name: name}),
+			ƒ.Code(µ.StatusCreated),
+		)
+	}
+
+	if cat := send("a")(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Error(cat.Fail)
+	}
+
+	if cat := send("b")(assay.IO(µ.Default())); cat.Fail == nil {
+		t.Error("unexpected request is matched")
+	}
+}
+
+func TestMockEmptyBody(t *testing.T) {
+	ts := mock.New(mock.On("POST", "/ping").Body(nil).Reply(http.StatusOK))
+	defer ts.Close()
+
+	send := func(content string) assay.Arrow {
+		return µ.Join(
+			ø.POST(ts.URL+"/ping"),
+			ø.Content().Is("text/plain"),
+			ø.Send(content),
+			ƒ.Code(µ.StatusOK),
+		)
+	}
+
+	if cat := send("")(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Error(cat.Fail)
+	}
+
+	if cat := send("ping")(assay.IO(µ.Default())); cat.Fail == nil {
+		t.Error("unexpected content is matched")
+	}
+}
+
+func TestMockWildcard(t *testing.T) {
+	ts := mock.New(mock.On("GET", "/static/*").Reply(http.StatusOK))
+	defer ts.Close()
+
+	f := µ.Join(ø.GET(ts.URL+"/static"), ƒ.Code(µ.StatusOK))
+	if cat := f(assay.IO(µ.Default())); cat.Fail == nil {
+		t.Error("wildcard matches empty suffix")
+	}
+}
+
+func TestMockReplyInvalid(t *testing.T) {
+	ts := mock.New(mock.On("GET", "/users").Reply(http.StatusOK, map[string]interface{}{"a": func() {}}))
+	defer ts.Close()
+
+	f := µ.Join(ø.GET(ts.URL+"/users"), ƒ.Code(µ.StatusOK))
+	if cat := f(assay.IO(µ.Default())); cat.Fail == nil {
+		t.Error("invalid reply is served")
+	}
+
+	if err := ts.Verify(); err == nil || !strings.Contains(err.Error(), "GET /users: invalid reply") {
+		t.Errorf("invalid reply is not reported %v", err)
+	}
+}
+
+func TestMockTimes(t *testing.T) {
+	ts := mock.New(
+		mock.On("GET", "/flaky").Times(1).Reply(http.StatusServiceUnavailable),
+		mock.On("GET", "/flaky").Reply(http.StatusOK),
+	)
+	defer ts.Close()
+
+	f := assay.Join(
+		µ.Join(ø.GET(ts.URL+"/flaky"), ƒ.Code(µ.StatusServiceUnavailable)),
+		µ.Join(ø.GET(ts.URL+"/flaky"), ƒ.Code(µ.StatusOK)),
+		µ.Join(ø.GET(ts.URL+"/flaky"), ƒ.Code(µ.StatusOK)),
+	)
+
+	if cat := f(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Error(cat.Fail)
+	}
+
+	if err := ts.Verify(); err != nil {
+		t.Error(err)
+	}
+}
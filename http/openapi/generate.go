@@ -0,0 +1,628 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+/*
+
+Generate writes Go source of scenario skeletons, one scenario per operation
+of the contract. Each scenario is registered to the suite, it sends the
+request with example values of params and decodes the response into typed
+struct derived from the schema.
+  func GetUser() assay.Arrow {
+    id := 1
+    var response User
+
+    return µ.Join(
+      ø.URL("GET", "%s/v1/users/%v", assay.Host("https://api.example.com"), id),
+      ø.Header("Accept").Is("application/json"),
+      ƒ.Code(µ.StatusOK),
+      ƒ.Recv(&response),
+    )
+  }
+Skeletons are the starting point of the suite, complete them with expectations
+of the service.
+*/
+func (c *Contract) Generate(w io.Writer, pkg string) error {
+	g := &generator{doc: c.doc, names: scope{}, types: map[string]string{}}
+
+	src, err := g.source(pkg)
+	if err != nil {
+		return err
+	}
+
+	if src, err = format.Source(src); err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+// generator builds Go source of scenarios, it keeps declared identifiers
+// to avoid collisions of type names
+type generator struct {
+	doc   *openapi3.T
+	names scope
+	types map[string]string
+	decls []string
+}
+
+// scope keeps identifiers declared within package, function or struct
+type scope map[string]bool
+
+// imports are names used by the body of scenario
+var imports = []string{"assay", "suite", "µ", "ƒ", "ø", "request", "response"}
+
+func newScope(reserved ...string) scope {
+	s := scope{}
+	for _, name := range reserved {
+		s[name] = true
+	}
+	return s
+}
+
+// unique returns unused identifier and declares it
+func (s scope) unique(name string) string {
+	id := name
+	for i := 2; s[id]; i++ {
+		id = fmt.Sprintf("%s%d", name, i)
+	}
+	s[id] = true
+	return id
+}
+
+func (g *generator) source(pkg string) ([]byte, error) {
+	components := []string{}
+	if g.doc.Components != nil {
+		for name := range g.doc.Components.Schemas {
+			components = append(components, name)
+		}
+	}
+	sort.Strings(components)
+
+	// Note: names of components are reserved before any inline type is
+	//       declared, so that components keep their names.
+	for _, name := range components {
+		g.types[name] = g.names.unique(ident(name))
+	}
+	for _, name := range components {
+		g.declare(g.types[name], "the schema "+name, g.doc.Components.Schemas[name])
+	}
+
+	paths := []string{}
+	for path := range g.doc.Paths.Map() {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	registry := &bytes.Buffer{}
+	scenarios := &bytes.Buffer{}
+	for _, path := range paths {
+		item := g.doc.Paths.Value(path)
+		for _, method := range methods {
+			if op := item.GetOperation(method); op != nil {
+				g.scenario(registry, scenarios, method, path, item, op)
+			}
+		}
+	}
+
+	if registry.Len() == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no operations")
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Scenarios are generated by assay from OpenAPI document %q.\n", g.doc.Info.Title)
+	fmt.Fprintf(buf, "// Complete them with expectations of the service.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+	fmt.Fprintf(buf, "import (\n")
+	fmt.Fprintf(buf, "\t\"github.com/assay-it/sdk-go/assay\"\n")
+	fmt.Fprintf(buf, "\tµ \"github.com/assay-it/sdk-go/http\"\n")
+	fmt.Fprintf(buf, "\tƒ \"github.com/assay-it/sdk-go/http/recv\"\n")
+	fmt.Fprintf(buf, "\tø \"github.com/assay-it/sdk-go/http/send\"\n")
+	fmt.Fprintf(buf, "\t\"github.com/assay-it/sdk-go/suite\"\n")
+	fmt.Fprintf(buf, ")\n\n")
+	fmt.Fprintf(buf, "func init() {\n%s}\n", registry)
+	buf.Write(scenarios.Bytes())
+	for _, decl := range g.decls {
+		fmt.Fprintf(buf, "\n%s", decl)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scenario generates the scenario of operation
+func (g *generator) scenario(registry, w *bytes.Buffer, method, path string, item *openapi3.PathItem, op *openapi3.Operation) {
+	id := op.OperationID
+	if id == "" {
+		id = strings.ToLower(method) + " " + path
+	}
+	name := g.names.unique(ident(id))
+
+	opts := ""
+	if len(op.Tags) > 0 {
+		opts += fmt.Sprintf(", suite.Tag(%s)", quoteAll(op.Tags))
+	}
+	if op.Summary != "" {
+		opts += fmt.Sprintf(", suite.Describe(%q)", op.Summary)
+	}
+	fmt.Fprintf(registry, "\tsuite.Register(%q, %s%s)\n", id, name, opts)
+
+	vars := []string{}
+	arrows := []string{}
+	local := newScope(imports...)
+
+	// request line
+	host, base := g.server()
+	uri, args := "%s"+strings.TrimSuffix(base, "/"), []string{fmt.Sprintf("assay.Host(%q)", host)}
+	params := parameters(item, op)
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		uri += "/"
+		for segment != "" {
+			i, j := strings.Index(segment, "{"), strings.Index(segment, "}")
+			if i == -1 || j < i {
+				uri += strings.ReplaceAll(segment, "%", "%%")
+				break
+			}
+
+			uri += strings.ReplaceAll(segment[:i], "%", "%%") + "%v"
+			v := local.unique(variable(segment[i+1 : j]))
+			vars = append(vars, fmt.Sprintf("%s := %s", v, literal(params.lookup("path", segment[i+1:j]))))
+			args = append(args, v)
+			segment = segment[j+1:]
+		}
+	}
+	arrows = append(arrows, fmt.Sprintf("ø.URL(%q, %q, %s)", method, uri, strings.Join(args, ", ")))
+
+	// response content negotiation
+	status, response := g.response(op)
+	accept, schema := media(response)
+	if accept != "" {
+		arrows = append(arrows, fmt.Sprintf("ø.Header(\"Accept\").Is(%q)", accept))
+	}
+
+	for _, p := range params {
+		if p.In == "header" && p.Required {
+			arrows = append(arrows, fmt.Sprintf("ø.Header(%q).Is(%s)", p.Name, strconv.Quote(text(example(p)))))
+		}
+	}
+
+	// query params
+	query := []*openapi3.Parameter{}
+	for _, p := range params {
+		if p.In == "query" {
+			query = append(query, p)
+		}
+	}
+	if len(query) > 0 {
+		arrows = append(arrows, fmt.Sprintf("ø.Params(%s)", g.query(name+"Params", query)))
+	}
+
+	// request body
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		content, schema := media(op.RequestBody.Value.Content)
+		if content != "" {
+			arrows = append(arrows, fmt.Sprintf("ø.Header(\"Content-Type\").Is(%q)", content))
+			if schema != nil {
+				vars = append(vars, fmt.Sprintf("var request %s", g.goType(schema, name+"Request")))
+				arrows = append(arrows, "ø.Send(request)")
+			} else {
+				arrows = append(arrows, "ø.Send(\"\")")
+			}
+		}
+	}
+
+	// response
+	arrows = append(arrows, fmt.Sprintf("ƒ.Code(%s)", statusCode(status)))
+	if schema != nil {
+		vars = append(vars, fmt.Sprintf("var response %s", g.goType(schema, name+"Response")))
+		arrows = append(arrows, "ƒ.Recv(&response)")
+	}
+
+	fmt.Fprintf(w, "\n// %s is the scenario of operation %s %s\n", name, method, path)
+	if op.Summary != "" {
+		fmt.Fprintf(w, "// %s\n", comment(op.Summary))
+	}
+	fmt.Fprintf(w, "func %s() assay.Arrow {\n", name)
+	for _, v := range vars {
+		fmt.Fprintf(w, "\t%s\n", v)
+	}
+	if len(vars) > 0 {
+		fmt.Fprintf(w, "\n")
+	}
+	fmt.Fprintf(w, "\treturn µ.Join(\n")
+	for _, arrow := range arrows {
+		fmt.Fprintf(w, "\t\t%s,\n", arrow)
+	}
+	fmt.Fprintf(w, "\t)\n}\n")
+}
+
+// server returns host and base path of the first server
+func (g *generator) server() (string, string) {
+	if len(g.doc.Servers) == 0 {
+		return "", "/"
+	}
+
+	server := g.doc.Servers[0]
+	addr := server.URL
+	for name, v := range server.Variables {
+		addr = strings.ReplaceAll(addr, "{"+name+"}", v.Default)
+	}
+
+	i := strings.Index(addr, "://")
+	if i == -1 {
+		return "", addr
+	}
+
+	if j := strings.Index(addr[i+3:], "/"); j != -1 {
+		return addr[:i+3+j], addr[i+3+j:]
+	}
+	return addr, "/"
+}
+
+// response selects the expected response of operation, it is the first
+// successful one or the default one.
+func (g *generator) response(op *openapi3.Operation) (int, openapi3.Content) {
+	if op.Responses == nil {
+		return 200, nil
+	}
+
+	codes := []string{}
+	for code := range op.Responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if code[0] != '2' {
+			continue
+		}
+
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			status = 200
+		}
+		return status, content(op.Responses.Value(code))
+	}
+
+	return 200, content(op.Responses.Default())
+}
+
+func content(ref *openapi3.ResponseRef) openapi3.Content {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	return ref.Value.Content
+}
+
+// media selects JSON media type of the content or the first one
+func media(content openapi3.Content) (string, *openapi3.SchemaRef) {
+	seq := []string{}
+	for mime := range content {
+		seq = append(seq, mime)
+	}
+	sort.Strings(seq)
+
+	if len(seq) == 0 {
+		return "", nil
+	}
+
+	mime := seq[0]
+	for _, x := range seq {
+		if x == "application/json" || strings.HasSuffix(x, "+json") {
+			mime = x
+			break
+		}
+	}
+	return mime, content[mime].Schema
+}
+
+func statusCode(status int) string {
+	switch status {
+	case 200:
+		return "µ.StatusOK"
+	case 201:
+		return "µ.StatusCreated"
+	case 202:
+		return "µ.StatusAccepted"
+	case 203:
+		return "µ.StatusNonAuthoritativeInfo"
+	case 204:
+		return "µ.StatusNoContent"
+	case 205:
+		return "µ.StatusResetContent"
+	case 206:
+		return "µ.StatusPartialContent"
+	default:
+		return fmt.Sprintf("µ.StatusCode(%d)", status)
+	}
+}
+
+//
+// params
+//
+
+type params []*openapi3.Parameter
+
+// parameters merges params of path item and operation, the operation
+// overrides params of path item.
+func parameters(item *openapi3.PathItem, op *openapi3.Operation) params {
+	seq := params{}
+	for _, refs := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+
+			p := ref.Value
+			if i := seq.index(p.In, p.Name); i != -1 {
+				seq[i] = p
+			} else {
+				seq = append(seq, p)
+			}
+		}
+	}
+	return seq
+}
+
+func (seq params) index(in, name string) int {
+	for i, p := range seq {
+		if p.In == in && p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (seq params) lookup(in, name string) *openapi3.Parameter {
+	if i := seq.index(in, name); i != -1 {
+		return seq[i]
+	}
+	return &openapi3.Parameter{In: in, Name: name}
+}
+
+// query declares struct of query params, the values are strings as
+// required by send.Params.
+func (g *generator) query(hint string, seq []*openapi3.Parameter) string {
+	name := g.names.unique(hint)
+
+	fields, values, local := &strings.Builder{}, []string{}, scope{}
+	for _, p := range seq {
+		tag := p.Name
+		if !p.Required {
+			tag += ",omitempty"
+		}
+		field := local.unique(ident(p.Name))
+		fmt.Fprintf(fields, "\t%s string `json:%q`\n", field, tag)
+
+		if p.Required {
+			values = append(values, fmt.Sprintf("%s: %s", field, strconv.Quote(text(example(p)))))
+		}
+	}
+
+	g.decls = append(g.decls,
+		fmt.Sprintf("// %s is query params of the request\ntype %s struct {\n%s}\n", name, name, fields),
+	)
+	return fmt.Sprintf("%s{%s}", name, strings.Join(values, ", "))
+}
+
+// example returns example value of the param
+func example(p *openapi3.Parameter) interface{} {
+	if p.Example != nil {
+		return p.Example
+	}
+
+	if p.Schema != nil && p.Schema.Value != nil {
+		s := p.Schema.Value
+		switch {
+		case s.Example != nil:
+			return s.Example
+		case s.Default != nil:
+			return s.Default
+		case len(s.Enum) > 0:
+			return s.Enum[0]
+		case s.Type.Is("integer") || s.Type.Is("number"):
+			return 1
+		case s.Type.Is("boolean"):
+			return true
+		}
+	}
+
+	return p.Name
+}
+
+// literal returns Go literal of param example
+func literal(p *openapi3.Parameter) string {
+	switch v := example(p).(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool, int, int64, float64:
+		return fmt.Sprintf("%v", v)
+	default:
+		return strconv.Quote(text(v))
+	}
+}
+
+func text(v interface{}) string {
+	return fmt.Sprintf("%v", v)
+}
+
+//
+// types
+//
+
+// goType returns Go type of the schema, it declares named struct for
+// inline objects.
+func (g *generator) goType(ref *openapi3.SchemaRef, hint string) string {
+	if ref == nil {
+		return "interface{}"
+	}
+
+	if strings.HasPrefix(ref.Ref, "#/components/schemas/") {
+		if name, ok := g.types[strings.TrimPrefix(ref.Ref, "#/components/schemas/")]; ok {
+			return name
+		}
+	}
+
+	s := ref.Value
+	if s == nil {
+		return "interface{}"
+	}
+
+	switch {
+	case s.Type.Is("string"):
+		return "string"
+	case s.Type.Is("integer") && s.Format == "int64":
+		return "int64"
+	case s.Type.Is("integer"):
+		return "int"
+	case s.Type.Is("number"):
+		return "float64"
+	case s.Type.Is("boolean"):
+		return "bool"
+	case s.Type.Is("array"):
+		return "[]" + g.goType(s.Items, hint+"Item")
+	case len(s.Properties) > 0:
+		name := g.names.unique(hint)
+		g.declare(name, "the inline schema", ref)
+		return name
+	case s.Type.Is("object") && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + g.goType(s.AdditionalProperties.Schema, hint+"Value")
+	case s.Type.Is("object"):
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+// declare appends type declaration of the schema
+func (g *generator) declare(name, origin string, ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+
+	s := ref.Value
+	doc := fmt.Sprintf("// %s is %s\n", name, origin)
+	if s.Description != "" {
+		doc = fmt.Sprintf("// %s %s\n", name, comment(s.Description))
+	}
+
+	if len(s.Properties) == 0 {
+		// Note: the reference to the component is not followed, otherwise
+		//       the type becomes an alias of itself
+		inline := &openapi3.SchemaRef{Value: s}
+		g.decls = append(g.decls, fmt.Sprintf("%stype %s %s\n", doc, name, g.goType(inline, name+"Value")))
+		return
+	}
+
+	required := map[string]bool{}
+	for _, x := range s.Required {
+		required[x] = true
+	}
+
+	keys := []string{}
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields, local := &strings.Builder{}, scope{}
+	for _, key := range keys {
+		tag := key
+		if !required[key] {
+			tag += ",omitempty"
+		}
+		field := local.unique(ident(key))
+		fmt.Fprintf(fields, "\t%s %s `json:%q`\n", field, g.goType(s.Properties[key], name+field), tag)
+	}
+
+	g.decls = append(g.decls, fmt.Sprintf("%stype %s struct {\n%s}\n", doc, name, fields))
+}
+
+//
+// identifiers
+//
+
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// ident converts name to exported Go identifier, e.g. user_id to UserID
+func ident(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	id := ""
+	for _, word := range words {
+		if initialisms[strings.ToUpper(word)] {
+			id += strings.ToUpper(word)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(word)
+		id += string(unicode.ToUpper(r)) + word[size:]
+	}
+
+	// Note: the identifier is exported only if it starts with upper case
+	//       letter, digits and letters without case are prefixed.
+	if r, _ := utf8.DecodeRuneInString(id); !unicode.IsUpper(r) {
+		id = "X" + id
+	}
+	return id
+}
+
+// variable converts name to unexported Go identifier, e.g. user_id to userID
+func variable(name string) string {
+	id := ident(name)
+	switch {
+	case initialisms[id]:
+		id = strings.ToLower(id)
+	default:
+		r, size := utf8.DecodeRuneInString(id)
+		id = string(unicode.ToLower(r)) + id[size:]
+	}
+
+	// Note: predeclared identifiers are not shadowed, generated code
+	//       refers to types like string or int.
+	if token.IsKeyword(id) || types.Universe.Lookup(id) != nil {
+		id += "_"
+	}
+	return id
+}
+
+func quoteAll(seq []string) string {
+	quoted := make([]string, len(seq))
+	for i, x := range seq {
+		quoted[i] = strconv.Quote(x)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// comment folds text to single line
+func comment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
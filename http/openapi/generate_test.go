@@ -0,0 +1,161 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package openapi_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/http/openapi"
+)
+
+func TestGenerate(t *testing.T) {
+	contract, err := openapi.Load("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := contract.Generate(buf, "suites"); err != nil {
+		t.Fatal(err)
+	}
+
+	src := buf.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "suites.go", src, 0); err != nil {
+		t.Fatalf("invalid Go source %v\n%s", err, src)
+	}
+
+	for _, expect := range []string{
+		"package suites",
+		`suite.Register("listPets", ListPets, suite.Tag("pets"), suite.Describe("List all pets"))`,
+		`suite.Register("get /pets/{pet_id}", GetPetsPetID)`,
+		`ø.URL("GET", "%s/api/pets", assay.Host("https://petstore.example.com"))`,
+		`ø.Header("X-Request-ID").Is("X-Request-ID")`,
+		`ø.Params(ListPetsParams{Limit: "10"})`,
+		`ø.Header("Content-Type").Is("application/json")`,
+		"var request CreatePetRequest",
+		"ø.Send(request)",
+		"petID := 1",
+		`ø.URL("DELETE", "%s/api/pets/%v", assay.Host("https://petstore.example.com"), petID)`,
+		`ø.URL("GET", "%s/api/pets/%v", assay.Host("https://petstore.example.com"), petID)`,
+		"ƒ.Code(µ.StatusCreated)",
+		"ƒ.Code(µ.StatusNoContent)",
+		"var response []Pet",
+		"ƒ.Recv(&response)",
+		"type Pets []Pet",
+		"// Pet is an animal of the store",
+		"ID         int64              `json:\"id\"`",
+		"Attributes map[string]float64 `json:\"attributes,omitempty\"`",
+		"Owner GetPetsPetIDResponseOwner `json:\"owner,omitempty\"`",
+		"Tag   string `json:\"tag,omitempty\"`",
+		"Échelle    float64            `json:\"échelle,omitempty\"`",
+	} {
+		if !strings.Contains(src, expect) {
+			t.Errorf("generated source do not contain %s\n%s", expect, src)
+		}
+	}
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go toolchain is required")
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain is not found")
+	}
+
+	for _, spec := range []string{
+		"testdata/petstore.yaml",
+		"testdata/collisions.yaml",
+	} {
+		contract, err := openapi.Load(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := contract.Generate(buf, "suites"); err != nil {
+			t.Fatal(err)
+		}
+
+		if out, err := vet(gobin, buf.Bytes()); err != nil {
+			t.Errorf("generated suite of %s is not compiled: %v\n%s\n%s", spec, err, out, buf)
+		}
+	}
+}
+
+func TestGenerateCollisions(t *testing.T) {
+	contract, err := openapi.Load("testdata/collisions.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := contract.Generate(buf, "suites"); err != nil {
+		t.Fatal(err)
+	}
+
+	src := buf.String()
+	for _, expect := range []string{
+		`suite.Register("getA", GetA)`,
+		`suite.Register("get_a", GetA2)`,
+		"assay2 := \"assay\"",
+		"userID := \"user_id\"",
+		"userID2 := \"user-id\"",
+		"response2 := \"response\"",
+		"string_ := \"string\"",
+		`assay.Host("https://api.example.com"), assay2, userID, userID2, response2, string_)`,
+		`ø.Params(GetAParams{PageSize: "1", PageSize2: "1"})`,
+		"var request User2",
+		"type User string",
+		"ID2   string `json:\"id,omitempty\"`",
+		"User2 User2  `json:\"user,omitempty\"`",
+		"Name2 string `json:\"name,omitempty\"`",
+	} {
+		if !strings.Contains(src, expect) {
+			t.Errorf("generated source do not contain %s\n%s", expect, src)
+		}
+	}
+}
+
+// vet writes the suite into the module, so that it is built with this
+// version of the sdk, and checks it with go vet.
+func vet(gobin string, src []byte) ([]byte, error) {
+	dir, err := ioutil.TempDir("testdata", "suites")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "suites.go"), src, 0644); err != nil {
+		return nil, err
+	}
+
+	return exec.Command(gobin, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput()
+}
+
+func TestGenerateNoOperations(t *testing.T) {
+	contract, err := openapi.Read(strings.NewReader(`{"openapi": "3.0.3", "info": {"title": "x", "version": "1"}, "paths": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := contract.Generate(&bytes.Buffer{}, "suites"); err == nil {
+		t.Error("empty contract is generated")
+	}
+}
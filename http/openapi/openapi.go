@@ -0,0 +1,466 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package openapi validates HTTP traffic of suites against OpenAPI 3 contract.
+Each request is validated against the matching operation (path, query and
+header params, request body) before it is sent, each response is validated
+against declared status codes, headers and schemas. Violations fail the
+category with assay.Mismatch error.
+
+  contract, err := openapi.Load("openapi.yaml")
+  suite.Main(contract.Validate())
+
+The contract counts exchanges of each operation, the coverage report lists
+operations never exercised by the suite
+
+  func TestContract(t *testing.T) {
+    suite.Run(t, contract.Validate())
+    t.Log(contract.Coverage())
+  }
+
+The contract matches requests by the path of servers, the host of servers is
+ignored so that the suite is runnable against any deployment.
+
+The package is the own module, kept apart from the core SDK, it depends on
+github.com/getkin/kin-openapi.
+
+  go get github.com/assay-it/sdk-go/http/openapi
+*/
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+/*
+
+Contract is OpenAPI 3 document used to validate HTTP traffic
+*/
+type Contract struct {
+	sync.Mutex
+	doc    *openapi3.T
+	router routers.Router
+	calls  map[string]int
+}
+
+/*
+
+Load reads OpenAPI 3 document (JSON or YAML) from the file, references to
+other files are resolved relative to it.
+*/
+func Load(path string) (*Contract, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(doc)
+}
+
+/*
+
+Read decodes OpenAPI 3 document (JSON or YAML) from the stream
+*/
+func Read(r io.Reader) (*Contract, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	return New(doc)
+}
+
+/*
+
+New creates the contract from OpenAPI 3 document
+*/
+func New(doc *openapi3.T) (*Contract, error) {
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	// Note: router is built from a copy of document, servers are replaced
+	//       with their paths so that requests to any host are matched.
+	spec := *doc
+	spec.Servers = relative(doc.Servers)
+
+	router, err := gorillamux.NewRouter(&spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Contract{doc: doc, router: router, calls: map[string]int{}}, nil
+}
+
+// relative strips scheme and host from servers
+func relative(servers openapi3.Servers) openapi3.Servers {
+	seq := openapi3.Servers{}
+	seen := map[string]bool{}
+
+	for _, server := range servers {
+		path := server.URL
+		if i := strings.Index(path, "://"); i != -1 {
+			path = path[i+3:]
+			if j := strings.Index(path, "/"); j != -1 {
+				path = path[j:]
+			} else {
+				path = "/"
+			}
+		}
+
+		if !seen[path] {
+			seen[path] = true
+			seq = append(seq, &openapi3.Server{URL: path, Variables: server.Variables})
+		}
+	}
+
+	if len(seq) == 0 {
+		seq = append(seq, &openapi3.Server{URL: "/"})
+	}
+	return seq
+}
+
+/*
+
+Validate configures the category to validate each HTTP exchange against the
+contract. The request is not sent if it violates the contract.
+*/
+func (c *Contract) Validate() assay.Config {
+	return assay.Intercept(func(f assay.Arrow) assay.Arrow {
+		return func(cat *assay.IOCat) *assay.IOCat {
+			if cat.HTTP == nil || cat.HTTP.Send == nil {
+				return f(cat)
+			}
+
+			var input *openapi3filter.RequestValidationInput
+			if input, cat.Fail = c.request(cat); cat.Fail != nil {
+				return cat
+			}
+
+			if cat = f(cat); cat.Fail != nil {
+				return cat
+			}
+
+			c.cover(input.Route)
+			cat.Fail = c.response(cat, input)
+			return cat
+		}
+	})
+}
+
+func options() *openapi3filter.Options {
+	return &openapi3filter.Options{
+		MultiError:            true,
+		IncludeResponseStatus: true,
+		SkipSettingDefaults:   true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+}
+
+// request matches the request with operation and validates it
+func (c *Contract) request(cat *assay.IOCat) (*openapi3filter.RequestValidationInput, error) {
+	send := cat.HTTP.Send
+
+	var payload []byte
+	if send.Payload != nil {
+		data, err := ioutil.ReadAll(send.Payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = data
+		send.Payload = bytes.NewReader(data)
+	}
+
+	eg, err := http.NewRequestWithContext(cat.Context(), send.Method, send.URL.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for head, value := range send.Header {
+		eg.Header.Set(head, *value)
+	}
+
+	route, params, err := c.router.FindRoute(eg)
+	if err != nil {
+		return nil, &assay.Mismatch{
+			Diff:    fmt.Sprintf("+ %s %s\n- operation: %v", send.Method, send.URL.Path, err),
+			Payload: nil,
+		}
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    eg,
+		PathParams: params,
+		Route:      route,
+		Options:    options(),
+	}
+
+	if err := openapi3filter.ValidateRequest(cat.Context(), input); err != nil {
+		return nil, violations(route, "request", err)
+	}
+
+	return input, nil
+}
+
+// response validates received response against the operation
+func (c *Contract) response(cat *assay.IOCat, input *openapi3filter.RequestValidationInput) error {
+	if cat.HTTP.Recv == nil || cat.HTTP.Recv.Response == nil {
+		return nil
+	}
+
+	recv := cat.HTTP.Recv
+	data, err := ioutil.ReadAll(recv.Body)
+	recv.Body.Close()
+	if err != nil {
+		return err
+	}
+	recv.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	output := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 recv.StatusCode,
+		Header:                 recv.Header,
+		Body:                   ioutil.NopCloser(bytes.NewReader(data)),
+		Options:                input.Options,
+	}
+
+	if err := openapi3filter.ValidateResponse(cat.Context(), output); err != nil {
+		return violations(input.Route, "response", err)
+	}
+	return nil
+}
+
+// violations lists each failure of validation
+func violations(route *routers.Route, scope string, err error) error {
+	seq := []string{fmt.Sprintf("+ %s %s", route.Method, route.Path)}
+	for _, e := range flatten(err) {
+		seq = append(seq, fmt.Sprintf("- %s %s", scope, explain(e)))
+	}
+
+	return &assay.Mismatch{
+		Diff:    strings.Join(seq, "\n"),
+		Payload: err,
+	}
+}
+
+// flatten unfolds multiple failures, each one keeps location of its cause
+func flatten(err error) []error {
+	seq := []error{}
+
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, x := range e {
+			seq = append(seq, flatten(x)...)
+		}
+	case *openapi3filter.RequestError:
+		for _, x := range causes(e.Err) {
+			cause := *e
+			cause.Err = x
+			seq = append(seq, &cause)
+		}
+	case *openapi3filter.ResponseError:
+		for _, x := range causes(e.Err) {
+			cause := *e
+			cause.Err = x
+			seq = append(seq, &cause)
+		}
+	default:
+		seq = append(seq, err)
+	}
+
+	return seq
+}
+
+func causes(err error) []error {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		seq := []error{}
+		for _, x := range multi {
+			seq = append(seq, causes(x)...)
+		}
+		return seq
+	}
+	return []error{err}
+}
+
+// explain renders failure as a single line, the location of invalid value
+// is rendered as JSON Pointer.
+func explain(err error) string {
+	var schema *openapi3.SchemaError
+	location, reason := "", err.Error()
+
+	switch e := err.(type) {
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			location = fmt.Sprintf("%s %s", e.Parameter.In, e.Parameter.Name)
+		case e.RequestBody != nil:
+			location = "body"
+		}
+		reason = e.Reason
+		if e.Err != nil {
+			reason = e.Err.Error()
+		}
+	case *openapi3filter.ResponseError:
+		if errors.As(err, &schema) {
+			location = "body"
+		}
+		reason = e.Reason
+		if e.Err != nil {
+			reason = e.Err.Error()
+		}
+	}
+
+	if errors.As(err, &schema) {
+		reason = fmt.Sprintf("%s: %s", pointer(schema.JSONPointer()), schema.Reason)
+	}
+
+	reason = strings.SplitN(reason, "\n", 2)[0]
+	if location == "" {
+		return reason
+	}
+	return fmt.Sprintf("%s: %s", location, reason)
+}
+
+func pointer(seq []string) string {
+	if len(seq) == 0 {
+		return "/"
+	}
+
+	path := ""
+	for _, x := range seq {
+		path += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(x)
+	}
+	return path
+}
+
+//
+// coverage
+//
+
+/*
+
+Operation of the contract and the number of its exchanges
+*/
+type Operation struct {
+	Method string
+	Path   string
+	ID     string
+	Calls  int
+}
+
+func (op Operation) String() string {
+	if op.ID == "" {
+		return fmt.Sprintf("%s %s", op.Method, op.Path)
+	}
+	return fmt.Sprintf("%s %s (%s)", op.Method, op.Path, op.ID)
+}
+
+/*
+
+Coverage of the contract by the suite
+*/
+type Coverage []Operation
+
+/*
+
+Missing returns operations never exercised by the suite
+*/
+func (cov Coverage) Missing() Coverage {
+	seq := Coverage{}
+	for _, op := range cov {
+		if op.Calls == 0 {
+			seq = append(seq, op)
+		}
+	}
+	return seq
+}
+
+/*
+
+Ratio returns the fraction of exercised operations
+*/
+func (cov Coverage) Ratio() float64 {
+	if len(cov) == 0 {
+		return 1
+	}
+	return float64(len(cov)-len(cov.Missing())) / float64(len(cov))
+}
+
+func (cov Coverage) String() string {
+	missing := cov.Missing()
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "%d of %d operations covered (%.1f%%)",
+		len(cov)-len(missing), len(cov), 100*cov.Ratio())
+	for _, op := range missing {
+		fmt.Fprintf(buf, "\n  not covered: %s", op)
+	}
+	return buf.String()
+}
+
+/*
+
+Coverage reports exchanges of each operation declared by the contract,
+operations are ordered by path and method.
+*/
+func (c *Contract) Coverage() Coverage {
+	c.Lock()
+	defer c.Unlock()
+
+	cov := Coverage{}
+	for path, item := range c.doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			cov = append(cov, Operation{
+				Method: method,
+				Path:   path,
+				ID:     op.OperationID,
+				Calls:  c.calls[key(method, path)],
+			})
+		}
+	}
+
+	sort.Slice(cov, func(i, j int) bool {
+		if cov[i].Path != cov[j].Path {
+			return cov[i].Path < cov[j].Path
+		}
+		return cov[i].Method < cov[j].Method
+	})
+	return cov
+}
+
+func (c *Contract) cover(route *routers.Route) {
+	c.Lock()
+	defer c.Unlock()
+	c.calls[key(route.Method, route.Path)]++
+}
+
+func key(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package openapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/http/openapi"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestContract(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	contract, err := openapi.Load("testdata/users.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var user User
+	req := assay.Join(
+		µ.Join(
+			ø.GET(ts.URL + "/v1/users/1"),
+			ø.Params(map[string]string{"verbose": "true"}),
+			ƒ.Code(µ.StatusOK),
+			ƒ.Recv(&user),
+		),
+		µ.Join(
+			ø.POST(ts.URL+"/v1/users"),
+			ø.ContentJSON(),
+			ø.Send(User{Name: "b"}),
+			ƒ.Code(µ.StatusCreated),
+		),
+	)
+
+	cat := assay.IO(µ.Default(), contract.Validate())
+	if cat = req(cat); cat.Fail != nil {
+		t.Fatal(cat.Fail)
+	}
+
+	if user.ID != 1 || user.Name != "a" {
+		t.Errorf("unexpected user %v", user)
+	}
+
+	cov := contract.Coverage()
+	if len(cov) != 3 || len(cov.Missing()) != 1 || cov.Missing()[0].ID != "deleteUser" {
+		t.Errorf("unexpected coverage %v", cov)
+	}
+
+	if s := cov.String(); !strings.HasPrefix(s, "2 of 3 operations covered (66.7%)") ||
+		!strings.Contains(s, "not covered: DELETE /users/{id} (deleteUser)") {
+		t.Errorf("unexpected coverage report %s", s)
+	}
+}
+
+func TestContractViolation(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	contract, err := openapi.Load("testdata/users.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for expect, req := range map[string]assay.Arrow{
+		"- operation: no matching operation was found": µ.Join(
+			ø.GET(ts.URL + "/v1/groups"),
+			ƒ.Code(µ.StatusOK),
+		),
+		"- request path id:": µ.Join(
+			ø.GET(ts.URL + "/v1/users/a"),
+			ƒ.Code(µ.StatusOK),
+		),
+		"- request query verbose:": µ.Join(
+			ø.GET(ts.URL + "/v1/users/1"),
+			ø.Params(map[string]string{"verbose": "maybe"}),
+			ƒ.Code(µ.StatusOK),
+		),
+		"- request body: /name:": µ.Join(
+			ø.POST(ts.URL+"/v1/users"),
+			ø.ContentJSON(),
+			ø.Send(map[string]int{"id": 1}),
+			ƒ.Code(µ.StatusCreated),
+		),
+		"- response body: /id:": µ.Join(
+			ø.GET(ts.URL + "/v1/users/2"),
+			ƒ.Code(µ.StatusOK),
+		),
+		"- response status is not supported": µ.Join(
+			ø.GET(ts.URL + "/v1/users/3"),
+			ƒ.Code(µ.StatusInternalServerError),
+		),
+	} {
+		var mismatch *assay.Mismatch
+		cat := req(assay.IO(µ.Default(), contract.Validate()))
+		if !errors.As(cat.Fail, &mismatch) || !strings.Contains(mismatch.Diff, expect) {
+			t.Errorf("unexpected violation, expected %q: %v", expect, cat.Fail)
+		}
+	}
+}
+
+func TestContractInvalid(t *testing.T) {
+	for _, spec := range []string{
+		`openapi: 3.0.3`,
+		`{"openapi": "3.0.3", "info": {"title": "x", "version": "1"}, "paths": {"/a": {"get": {}}}}`,
+	} {
+		if _, err := openapi.Read(strings.NewReader(spec)); err == nil {
+			t.Errorf("invalid contract is accepted %s", spec)
+		}
+	}
+
+	if _, err := openapi.Load("testdata/none.yaml"); err == nil {
+		t.Error("missing contract is accepted")
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/v1/users":
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": 3, "name": "b"}`))
+			case r.URL.Path == "/v1/users/1":
+				w.Write([]byte(`{"id": 1, "name": "a"}`))
+			case r.URL.Path == "/v1/users/2":
+				w.Write([]byte(`{"id": "2", "name": "b"}`))
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}),
+	)
+}
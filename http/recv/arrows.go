@@ -14,6 +14,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/ajg/form"
 	"github.com/assay-it/sdk-go/assay"
@@ -113,11 +114,71 @@ func (header THeader) Any() http.Arrow {
 	return header.Is("*")
 }
 
+// TTiming is tagged type, represents latency metric of HTTP request
+type TTiming struct {
+	metric string
+	value  func(assay.Timing) time.Duration
+}
+
+// DNS is latency of DNS lookup
+func DNS() TTiming {
+	return TTiming{"dns", func(t assay.Timing) time.Duration { return t.DNS }}
+}
+
+// Connect is latency of TCP connection
+func Connect() TTiming {
+	return TTiming{"connect", func(t assay.Timing) time.Duration { return t.Connect }}
+}
+
+// TLS is latency of TLS handshake
+func TLS() TTiming {
+	return TTiming{"tls", func(t assay.Timing) time.Duration { return t.TLS }}
+}
+
+// TTFB is time to first byte of response
+func TTFB() TTiming {
+	return TTiming{"ttfb", func(t assay.Timing) time.Duration { return t.TTFB }}
+}
+
+// Latency is total latency of request
+func Latency() TTiming {
+	return TTiming{"total", func(t assay.Timing) time.Duration { return t.Total }}
+}
+
+/*
+
+Within matches latency of request against the budget. The execution fails
+with OverBudget error if the latency exceeds the budget.
+  http.Join(
+    ...
+    ƒ.Code(http.StatusOK),
+    ƒ.TTFB().Within(200*time.Millisecond),
+  )
+*/
+func (timing TTiming) Within(budget time.Duration) http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		if cat.HTTP == nil || cat.HTTP.Recv == nil {
+			cat.Fail = &assay.Undefined{Type: "timing"}
+			return cat
+		}
+
+		if actual := timing.value(cat.HTTP.Recv.Timing); actual > budget {
+			cat.Fail = &assay.OverBudget{
+				Metric: timing.metric,
+				Budget: budget,
+				Actual: actual,
+			}
+		}
+		return cat
+	}
+}
+
 /*
 
 Recv applies auto decoders for response and returns either binary or
 native Go data structure. The Content-Type header give a hint to decoder.
-Supply the pointer to data target data structure.
+Supply the pointer to data target data structure. Use RecvStrict to reject
+unknown fields of JSON documents.
 */
 func Recv(out interface{}) http.Arrow {
 	return func(cat *assay.IOCat) *assay.IOCat {
@@ -154,7 +215,9 @@ Bytes receive raw binary from HTTP response
 func Bytes(val *[]byte) http.Arrow {
 	return func(cat *assay.IOCat) *assay.IOCat {
 		*val, cat.Fail = ioutil.ReadAll(cat.HTTP.Recv.Body)
-		cat.Fail = cat.HTTP.Recv.Body.Close()
+		if err := cat.HTTP.Recv.Body.Close(); cat.Fail == nil {
+			cat.Fail = err
+		}
 		cat.HTTP.Recv.Response = nil
 		cat.HTTP.Recv.Payload = string(*val)
 		return cat
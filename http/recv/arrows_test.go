@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/assay-it/sdk-go/assay"
 	µ "github.com/assay-it/sdk-go/http"
@@ -222,6 +223,47 @@ func TestRecvBytes(t *testing.T) {
 	}
 }
 
+func TestTiming(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := µ.Join(
+		ø.GET(ts.URL+"/json"),
+		ƒ.Code(µ.StatusOK),
+		ƒ.DNS().Within(time.Second),
+		ƒ.Connect().Within(time.Second),
+		ƒ.TLS().Within(time.Second),
+		ƒ.TTFB().Within(time.Second),
+		ƒ.Latency().Within(time.Second),
+	)
+	cat := assay.IO(µ.Default())
+
+	if cat = req(cat); cat.Fail != nil {
+		t.Error("failed to match latency")
+	}
+
+	if cat.HTTP.Recv.Timing.TTFB == 0 || cat.HTTP.Recv.Timing.Total < cat.HTTP.Recv.Timing.TTFB {
+		t.Error("failed to measure timing")
+	}
+}
+
+func TestTimingOverBudget(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := µ.Join(
+		ø.GET(ts.URL+"/slow"),
+		ƒ.Code(µ.StatusOK),
+		ƒ.TTFB().Within(10*time.Millisecond),
+	)
+	cat := assay.IO(µ.Default())
+
+	var e *assay.OverBudget
+	if cat = req(cat); !errors.As(cat.Fail, &e) || e.Metric != "ttfb" || e.Actual < 50*time.Millisecond {
+		t.Error("failed to detect latency over budget")
+	}
+}
+
 //
 func mock() *httptest.Server {
 	return httptest.NewServer(
@@ -230,6 +272,15 @@ func mock() *httptest.Server {
 			case r.URL.Path == "/json":
 				w.Header().Add("Content-Type", "application/json")
 				w.Write([]byte(`{"site": "example.com"}`))
+			case r.URL.Path == "/doc":
+				w.Header().Add("Content-Type", "application/json")
+				w.Write([]byte(`{"site": "example.com", "a/b": 1, "users": [{"id": "1", "age": 30}, {"id": "2", "age": 40, "tags": null}]}`))
+			case r.URL.Path == "/echo":
+				w.Header().Add("Content-Type", "application/json")
+				w.Write([]byte(r.URL.Query().Get("json")))
+			case r.URL.Path == "/slow":
+				time.Sleep(50 * time.Millisecond)
+				w.Write([]byte("slow"))
 			case r.URL.Path == "/form":
 				w.Header().Add("Content-Type", "application/x-www-form-urlencoded")
 				w.Write([]byte("site=example.com"))
@@ -0,0 +1,471 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package recv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+	"github.com/google/go-cmp/cmp"
+)
+
+/*
+
+TJSON is a lens, which focuses on the part of received JSON document.
+The lens is built either from JSON Pointer (RFC 6901) or JSONPath.
+  http.Join(
+    ...
+    ƒ.Code(http.StatusOK),
+    ƒ.Pointer("/user/name").Is("a"),
+    ƒ.Path("$.items[*].id").Exists(),
+  )
+Lenses do not consume the response, they are composable with each other
+and with Recv.
+*/
+type TJSON struct {
+	path  string
+	err   error
+	focus func(interface{}) (interface{}, bool)
+}
+
+/*
+
+Pointer focuses on the value identified by JSON Pointer, e.g. /users/0/name
+*/
+func Pointer(pointer string) TJSON {
+	seq, err := parsePointer(pointer)
+	if err != nil {
+		return TJSON{path: pointer, err: err}
+	}
+	return TJSON{path: pointer, focus: func(doc interface{}) (interface{}, bool) { return lookup(doc, seq) }}
+}
+
+/*
+
+Path focuses on the value identified by JSONPath. The subset of JSONPath
+is supported: root $, child .name or ['name'], index [0] or [-1] and
+wildcard .* or [*]. The wildcard focuses on array of matched values.
+  ƒ.Path("$.users[0].name")
+  ƒ.Path("$.users[*].id")
+*/
+func Path(path string) TJSON {
+	seq, err := parsePath(path)
+	if err != nil {
+		return TJSON{path: path, err: err}
+	}
+	return TJSON{path: path, focus: func(doc interface{}) (interface{}, bool) { return query(doc, seq) }}
+}
+
+// Is matches the focused value with the expected one
+func (lens TJSON) Is(expect interface{}) http.Arrow {
+	return lens.check(func(actual interface{}) string {
+		var value interface{}
+		if err := normalize(expect, &value); err != nil {
+			return err.Error()
+		}
+		return cmp.Diff(actual, value)
+	})
+}
+
+// Exists matches presence of the focused value
+func (lens TJSON) Exists() http.Arrow {
+	return lens.check(func(interface{}) string { return "" })
+}
+
+// Missing matches absence of the focused value
+func (lens TJSON) Missing() http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		var actual interface{}
+		var ok bool
+		if actual, ok, cat.Fail = lens.value(cat); cat.Fail != nil {
+			return cat
+		}
+
+		if ok {
+			cat.Fail = &assay.Mismatch{
+				Diff:    fmt.Sprintf("+ %s: %v\n- %s: <missing>", lens.path, actual, lens.path),
+				Payload: actual,
+			}
+		}
+		return cat
+	}
+}
+
+/*
+
+Type matches JSON type of the focused value: null, boolean, number, string,
+array or object
+*/
+func (lens TJSON) Type(kind string) http.Arrow {
+	return lens.check(func(actual interface{}) string {
+		if t := typeOf(actual); t != kind {
+			return fmt.Sprintf("+ type: %s\n- type: %s", t, kind)
+		}
+		return ""
+	})
+}
+
+// Match matches the focused string with the regular expression
+func (lens TJSON) Match(pattern string) http.Arrow {
+	re, err := regexp.Compile(pattern)
+	return lens.check(func(actual interface{}) string {
+		if err != nil {
+			return err.Error()
+		}
+
+		s, ok := actual.(string)
+		if !ok {
+			return fmt.Sprintf("+ type: %s\n- type: string", typeOf(actual))
+		}
+
+		if !re.MatchString(s) {
+			return fmt.Sprintf("+ %q\n- /%s/", s, pattern)
+		}
+		return ""
+	})
+}
+
+// Range matches the focused number within the closed interval [min, max]
+func (lens TJSON) Range(min, max float64) http.Arrow {
+	return lens.check(func(actual interface{}) string {
+		x, ok := actual.(float64)
+		if !ok {
+			return fmt.Sprintf("+ type: %s\n- type: number", typeOf(actual))
+		}
+
+		if x < min || x > max {
+			return fmt.Sprintf("+ %v\n- [%v, %v]", x, min, max)
+		}
+		return ""
+	})
+}
+
+// To extracts the focused value into the variable, supply the pointer to it
+func (lens TJSON) To(out interface{}) http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		var actual interface{}
+		var ok bool
+		if actual, ok, cat.Fail = lens.value(cat); cat.Fail != nil {
+			return cat
+		}
+
+		if !ok {
+			cat.Fail = lens.missing()
+			return cat
+		}
+
+		if err := normalize(actual, out); err != nil {
+			cat.Fail = &assay.Mismatch{
+				Diff:    fmt.Sprintf("%s: %v", lens.path, err),
+				Payload: actual,
+			}
+		}
+		return cat
+	}
+}
+
+func (lens TJSON) check(f func(interface{}) string) http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		var actual interface{}
+		var ok bool
+		if actual, ok, cat.Fail = lens.value(cat); cat.Fail != nil {
+			return cat
+		}
+
+		if !ok {
+			cat.Fail = lens.missing()
+			return cat
+		}
+
+		if diff := f(actual); diff != "" {
+			cat.Fail = &assay.Mismatch{
+				Diff:    fmt.Sprintf("%s:\n%s", lens.path, diff),
+				Payload: actual,
+			}
+		}
+		return cat
+	}
+}
+
+// value focuses on the value of received document
+func (lens TJSON) value(cat *assay.IOCat) (interface{}, bool, error) {
+	if lens.err != nil {
+		return nil, false, lens.err
+	}
+
+	doc, err := document(cat)
+	if err != nil {
+		return nil, false, err
+	}
+
+	actual, ok := lens.focus(doc)
+	return actual, ok, nil
+}
+
+func (lens TJSON) missing() error {
+	return &assay.Mismatch{
+		Diff:    fmt.Sprintf("+ %s: <missing>\n- %s: *", lens.path, lens.path),
+		Payload: nil,
+	}
+}
+
+//
+// JSON document of response
+//
+
+// jsonBody keeps decoded document, the content remains readable by other arrows
+type jsonBody struct {
+	io.Reader
+	doc interface{}
+}
+
+func (b *jsonBody) Close() error { return nil }
+
+// document decodes the response once and caches the document
+func document(cat *assay.IOCat) (interface{}, error) {
+	if cat.HTTP == nil || cat.HTTP.Recv == nil {
+		return nil, &assay.Undefined{Type: "JSON"}
+	}
+
+	recv := cat.HTTP.Recv
+	if recv.Response == nil {
+		// the response is consumed by Recv or Bytes arrows
+		var doc interface{}
+		switch v := recv.Payload.(type) {
+		case string:
+			err := json.Unmarshal([]byte(v), &doc)
+			return doc, notJSON(err, v)
+		default:
+			return doc, normalize(v, &doc)
+		}
+	}
+
+	if body, ok := recv.Body.(*jsonBody); ok {
+		return body.doc, nil
+	}
+
+	data, err := ioutil.ReadAll(recv.Body)
+	recv.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		recv.Body = ioutil.NopCloser(bytes.NewReader(data))
+		return nil, notJSON(err, string(data))
+	}
+
+	recv.Body = &jsonBody{Reader: bytes.NewReader(data), doc: doc}
+	return doc, nil
+}
+
+func notJSON(err error, content string) error {
+	if err == nil {
+		return nil
+	}
+	return &assay.Mismatch{
+		Diff:    fmt.Sprintf("+ %s\n- JSON document: %v", content, err),
+		Payload: content,
+	}
+}
+
+// normalize converts the value to JSON and decodes it to target
+func normalize(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+//
+// JSON Pointer
+//
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %s", pointer)
+	}
+
+	seq := strings.Split(pointer[1:], "/")
+	for i, token := range seq {
+		seq[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return seq, nil
+}
+
+func lookup(doc interface{}, seq []string) (interface{}, bool) {
+	for _, token := range seq {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			val, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			doc = val
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) || (len(token) > 1 && token[0] == '0') {
+				return nil, false
+			}
+			doc = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+//
+// JSONPath
+//
+
+// step of JSONPath is either key, index or wildcard
+type step struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+var (
+	pathKey   = regexp.MustCompile(`^\.([A-Za-z_$][\w$-]*|\*)`)
+	pathIndex = regexp.MustCompile(`^\[\s*(-?\d+|\*|'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*")\s*\]`)
+)
+
+func parsePath(path string) ([]step, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %s", path)
+	}
+
+	seq := []step{}
+	for s := path[1:]; s != ""; {
+		if m := pathKey.FindStringSubmatch(s); m != nil {
+			if m[1] == "*" {
+				seq = append(seq, step{wildcard: true})
+			} else {
+				seq = append(seq, step{key: m[1]})
+			}
+			s = s[len(m[0]):]
+			continue
+		}
+
+		if m := pathIndex.FindStringSubmatch(s); m != nil {
+			switch token := m[1]; {
+			case token == "*":
+				seq = append(seq, step{wildcard: true})
+			case token[0] == '\'' || token[0] == '"':
+				seq = append(seq, step{key: unquote(token)})
+			default:
+				i, _ := strconv.Atoi(token)
+				seq = append(seq, step{index: i, isIndex: true})
+			}
+			s = s[len(m[0]):]
+			continue
+		}
+
+		return nil, fmt.Errorf("invalid JSONPath %s at %s", path, s)
+	}
+	return seq, nil
+}
+
+func unquote(token string) string {
+	s := token[1 : len(token)-1]
+	return strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+func query(doc interface{}, seq []step) (interface{}, bool) {
+	nodes, wildcard := []interface{}{doc}, false
+
+	for _, st := range seq {
+		next := []interface{}{}
+		wildcard = wildcard || st.wildcard
+
+		for _, node := range nodes {
+			switch v := node.(type) {
+			case map[string]interface{}:
+				switch {
+				case st.wildcard:
+					for _, key := range sortedKeys(v) {
+						next = append(next, v[key])
+					}
+				case !st.isIndex:
+					if val, ok := v[st.key]; ok {
+						next = append(next, val)
+					}
+				}
+			case []interface{}:
+				switch {
+				case st.wildcard:
+					next = append(next, v...)
+				case st.isIndex:
+					i := st.index
+					if i < 0 {
+						i += len(v)
+					}
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+
+	switch {
+	case wildcard:
+		return nodes, len(nodes) > 0
+	case len(nodes) == 1:
+		return nodes[0], true
+	default:
+		return nil, false
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
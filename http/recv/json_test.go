@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package recv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+func TestJSONLens(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	type User struct {
+		ID  string `json:"id"`
+		Age int    `json:"age"`
+	}
+
+	var id string
+	var users []User
+	var site struct {
+		Site string `json:"site"`
+	}
+
+	req := µ.Join(
+		ø.GET(ts.URL+"/doc"),
+		ƒ.Code(µ.StatusOK),
+		ƒ.Pointer("/site").Is("example.com"),
+		ƒ.Pointer("/a~1b").Is(1),
+		ƒ.Pointer("/users/1/id").Is("2"),
+		ƒ.Pointer("/users/0").Is(User{ID: "1", Age: 30}),
+		ƒ.Pointer("/users/1/tags").Exists(),
+		ƒ.Pointer("/users/0/tags").Missing(),
+		ƒ.Pointer("/users").Type("array"),
+		ƒ.Pointer("/users/1/tags").Type("null"),
+		ƒ.Path("$.users[-1].age").Range(18, 65),
+		ƒ.Path("$['site']").Match(`^example\.(com|net)$`),
+		ƒ.Path("$.users[*].id").Is([]string{"1", "2"}),
+		ƒ.Path("$.users[0].id").To(&id),
+		ƒ.Path("$.users").To(&users),
+		ƒ.ServedJSON(),
+		ƒ.Recv(&site),
+	)
+
+	if cat := req(assay.IO(µ.Default())); cat.Fail != nil {
+		t.Fatal(cat.Fail)
+	}
+
+	if id != "1" || len(users) != 2 || users[1].Age != 40 || site.Site != "example.com" {
+		t.Errorf("unable to extract values %v %v %v", id, users, site)
+	}
+}
+
+func TestJSONLensMismatch(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for lens, diff := range map[string]µ.Arrow{
+		"/users/0/id:":               ƒ.Pointer("/users/0/id").Is("2"),
+		"+ /users/5: <missing>":      ƒ.Pointer("/users/5").Exists(),
+		"+ $.none: <missing>":        ƒ.Path("$.none").Is(1),
+		"+ type: string":             ƒ.Path("$.site").Type("number"),
+		"- /^www/":                   ƒ.Path("$.site").Match("^www"),
+		"- [50, 60]":                 ƒ.Path("$.users[0].age").Range(50, 60),
+		"+ /site: example.com":       ƒ.Pointer("/site").Missing(),
+		"$.users[*].age:":            ƒ.Path("$.users[*].age").Is([]int{30}),
+		"+ /users/0/id/x: <missing>": ƒ.Pointer("/users/0/id/x").Exists(),
+	} {
+		req := µ.Join(
+			ø.GET(ts.URL+"/doc"),
+			ƒ.Code(µ.StatusOK),
+			diff,
+		)
+
+		var mismatch *assay.Mismatch
+		cat := req(assay.IO(µ.Default()))
+		if !errors.As(cat.Fail, &mismatch) || !strings.Contains(mismatch.Diff, lens) {
+			t.Errorf("unexpected failure for %s: %v", lens, cat.Fail)
+		}
+	}
+}
+
+func TestJSONLensInvalid(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for _, lens := range []µ.Arrow{
+		ƒ.Pointer("users").Exists(),
+		ƒ.Path("users").Exists(),
+		ƒ.Path("$.users[").Exists(),
+	} {
+		req := µ.Join(
+			ø.GET(ts.URL+"/doc"),
+			ƒ.Code(µ.StatusOK),
+			lens,
+		)
+
+		if cat := req(assay.IO(µ.Default())); cat.Fail == nil {
+			t.Error("invalid lens is accepted")
+		}
+	}
+
+	req := µ.Join(
+		ø.GET(ts.URL+"/slow"),
+		ƒ.Code(µ.StatusOK),
+		ƒ.Pointer("/a").Exists(),
+	)
+
+	var mismatch *assay.Mismatch
+	if cat := req(assay.IO(µ.Default())); !errors.As(cat.Fail, &mismatch) {
+		t.Errorf("non JSON document is accepted: %v", cat.Fail)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/assay-it/sdk-go/http/recv/schema
+
+go 1.22
+
+require (
+	github.com/assay-it/sdk-go v0.0.0-00010101000000-000000000000
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
+)
+
+require (
+	github.com/ajg/form v1.5.1 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/assay-it/sdk-go => ../../..
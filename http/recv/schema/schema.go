@@ -0,0 +1,175 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package schema validates received JSON documents against JSON Schema. It is
+the own module, kept apart from the core SDK, the validator depends on io/fs
+and github.com/santhosh-tekuri/jsonschema/v6.
+
+  go get github.com/assay-it/sdk-go/http/recv/schema
+
+  http.Join(
+    ...
+    ƒ.Code(http.StatusOK),
+    schema.JSON(`{"type": "object", "required": ["id"]}`),
+    ƒ.Recv(&user),
+  )
+*/
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+/*
+
+JSON validates received JSON document against JSON Schema (draft 2020-12
+unless schema declares other one with $schema). The execution fails with
+Mismatch error, which lists each violation with JSON Pointer to the value.
+  http.Join(
+    ...
+    ƒ.Code(http.StatusOK),
+    schema.JSON(`{"type": "object", "required": ["id"]}`),
+    ƒ.Recv(&user),
+  )
+The arrow does not consume the response, it is composable with Recv and lenses.
+*/
+func JSON(schema string) http.Arrow {
+	const loc = "mem:///schema.json"
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return invalidSchema(err)
+	}
+
+	c := compiler()
+	if err := c.AddResource(loc, doc); err != nil {
+		return invalidSchema(err)
+	}
+	return validate(c.Compile(loc))
+}
+
+/*
+
+File validates received JSON document against JSON Schema from file.
+References to other schemas are resolved relative to the file.
+*/
+func File(path string) http.Arrow {
+	return validate(compiler().Compile(path))
+}
+
+/*
+
+FS validates received JSON document against JSON Schema from the file
+system, e.g. embed.FS. References to other schemas are resolved relative to
+the path within the file system.
+  //go:embed schema
+  var schemas embed.FS
+
+  schema.FS(schemas, "schema/user.json")
+*/
+func FS(fsys fs.FS, path string) http.Arrow {
+	c := compiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{"fs": fsLoader{fsys}})
+	return validate(c.Compile("fs:///" + strings.TrimPrefix(path, "/")))
+}
+
+func compiler() *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	return c
+}
+
+func invalidSchema(err error) http.Arrow {
+	return validate(nil, err)
+}
+
+func validate(schema *jsonschema.Schema, err error) http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		if err != nil {
+			cat.Fail = fmt.Errorf("invalid JSON schema: %w", err)
+			return cat
+		}
+
+		// Note: the lens on root decodes the document without consuming
+		//       the response, the body remains readable by other arrows.
+		var doc interface{}
+		if cat = ƒ.Pointer("").To(&doc)(cat); cat.Fail != nil {
+			return cat
+		}
+
+		if err := schema.Validate(doc); err != nil {
+			cat.Fail = violations(err, doc)
+		}
+		return cat
+	}
+}
+
+// violations lists each failed keyword of schema with location of the value
+func violations(err error, doc interface{}) error {
+	invalid, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	seq := []string{}
+	for _, unit := range invalid.BasicOutput().Errors {
+		if unit.Error == nil || isGroup(unit.Error.Kind) {
+			continue
+		}
+
+		path := unit.InstanceLocation
+		if path == "" {
+			path = "/"
+		}
+		seq = append(seq, fmt.Sprintf("- %s: %s (%s)", path, unit.Error, unit.KeywordLocation))
+	}
+
+	return &assay.Mismatch{
+		Diff:    strings.Join(seq, "\n"),
+		Payload: doc,
+	}
+}
+
+// isGroup checks if error only aggregates violations of nested keywords
+func isGroup(k jsonschema.ErrorKind) bool {
+	switch k.(type) {
+	case *kind.Group, *kind.Schema, *kind.Reference:
+		return true
+	default:
+		return false
+	}
+}
+
+// fsLoader loads schemas from file system, the url is fs:///path
+type fsLoader struct{ fs.FS }
+
+func (l fsLoader) Load(loc string) (interface{}, error) {
+	u, err := url.Parse(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := l.Open(strings.TrimPrefix(u.Path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return jsonschema.UnmarshalJSON(f)
+}
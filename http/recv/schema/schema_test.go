@@ -0,0 +1,118 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package schema_test
+
+import (
+	"embed"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	"github.com/assay-it/sdk-go/http/recv/schema"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+//go:embed testdata
+var schemas embed.FS
+
+func TestSchema(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for _, arrow := range []µ.Arrow{
+		schema.JSON(`{"type": "object", "required": ["site"]}`),
+		schema.File("testdata/doc.json"),
+		schema.FS(schemas, "testdata/doc.json"),
+	} {
+		var site struct {
+			Site string `json:"site"`
+		}
+
+		req := µ.Join(
+			ø.GET(ts.URL+"/doc"),
+			ƒ.Code(µ.StatusOK),
+			arrow,
+			ƒ.Recv(&site),
+		)
+
+		if cat := req(assay.IO(µ.Default())); cat.Fail != nil || site.Site != "example.com" {
+			t.Error(cat.Fail)
+		}
+	}
+}
+
+func TestSchemaViolation(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for diff, arrow := range map[string]µ.Arrow{
+		"- /: missing property 'host' (/required)\n- /users/0/id: got string, want integer (/properties/users/items/properties/id/type)\n- /users/1/id: got string, want integer (/properties/users/items/properties/id/type)": schema.JSON(`{
+			"type": "object",
+			"required": ["host"],
+			"properties": {
+				"users": {"items": {"properties": {"id": {"type": "integer"}}}}
+			}
+		}`),
+		"- /: missing properties 'id', 'age' (/required)": schema.FS(schemas, "testdata/user.json"),
+	} {
+		req := µ.Join(
+			ø.GET(ts.URL+"/doc"),
+			ƒ.Code(µ.StatusOK),
+			arrow,
+		)
+
+		var mismatch *assay.Mismatch
+		cat := req(assay.IO(µ.Default()))
+		if !errors.As(cat.Fail, &mismatch) || mismatch.Diff != diff {
+			t.Errorf("unexpected schema violation: %v", cat.Fail)
+		}
+	}
+}
+
+func TestSchemaInvalid(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for _, arrow := range []µ.Arrow{
+		schema.JSON(`{"type": `),
+		schema.JSON(`{"type": "unknown"}`),
+		schema.File("testdata/none.json"),
+		schema.FS(schemas, "testdata/none.json"),
+	} {
+		req := µ.Join(
+			ø.GET(ts.URL+"/doc"),
+			ƒ.Code(µ.StatusOK),
+			arrow,
+		)
+
+		if cat := req(assay.IO(µ.Default())); cat.Fail == nil || !strings.Contains(cat.Fail.Error(), "invalid JSON schema") {
+			t.Errorf("invalid schema is accepted: %v", cat.Fail)
+		}
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/doc":
+				w.Header().Add("Content-Type", "application/json")
+				w.Write([]byte(`{"site": "example.com", "a/b": 1, "users": [{"id": "1", "age": 30}, {"id": "2", "age": 40, "tags": null}]}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}),
+	)
+}
@@ -0,0 +1,336 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package recv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+)
+
+/*
+
+StrictOption configures strict decoding of RecvStrict
+*/
+type StrictOption func(*strict)
+
+type strict struct {
+	required bool
+}
+
+/*
+
+RequireFields demands presence of all struct fields, which are not tagged
+with omitempty.
+*/
+func RequireFields() StrictOption {
+	return func(s *strict) {
+		s.required = true
+	}
+}
+
+/*
+
+RecvStrict is the strict variant of Recv for JSON content. Unlike Recv, it
+fails with Mismatch error if the document contains fields unknown to the
+target data structure, duplicate keys or trailing data after the document.
+Each offender is listed with JSON Pointer to the value.
+  http.Join(
+    ...
+    ƒ.Code(http.StatusOK),
+    ƒ.RecvStrict(&user, ƒ.RequireFields()),
+  )
+Other content types are decoded as Recv does.
+*/
+func RecvStrict(out interface{}, opts ...StrictOption) http.Arrow {
+	config := strict{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(cat *assay.IOCat) *assay.IOCat {
+		content := cat.HTTP.Recv.Header.Get("Content-Type")
+		if !strings.Contains(content, "json") {
+			return Recv(out)(cat)
+		}
+
+		var data []byte
+		data, cat.Fail = ioutil.ReadAll(cat.HTTP.Recv.Body)
+		cat.HTTP.Recv.Body.Close()
+		cat.HTTP.Recv.Response = nil
+		if cat.Fail != nil {
+			return cat
+		}
+
+		if cat.Fail = config.decode(data, out); cat.Fail != nil {
+			return cat
+		}
+
+		cat.HTTP.Recv.Payload = out
+		return cat
+	}
+}
+
+func (s strict) decode(data []byte, out interface{}) error {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&doc); err != nil {
+		return notJSON(err, string(data))
+	}
+
+	seq := duplicates(data, reflect.TypeOf(out))
+	if rest := bytes.TrimSpace(data[dec.InputOffset():]); len(rest) > 0 {
+		seq = append(seq, fmt.Sprintf("+ trailing data: %s", rest))
+	}
+	seq = append(seq, s.fields(doc, reflect.TypeOf(out), "")...)
+
+	if len(seq) > 0 {
+		return &assay.Mismatch{
+			Diff:    strings.Join(seq, "\n"),
+			Payload: doc,
+		}
+	}
+
+	return json.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
+
+// duplicates lists keys, which are defined multiple times within object.
+// The document is walked along the type. Keys of objects decoded to struct
+// are compared case-insensitively, encoding/json matches them with fields
+// in the same way so that the last one silently wins. Keys of other objects
+// are compared exactly.
+func duplicates(data []byte, t reflect.Type) []string {
+	seq := []string{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string, t reflect.Type)
+	walk = func(path string, t reflect.Type) {
+		token, err := dec.Token()
+		if err != nil {
+			return
+		}
+
+		t = target(t)
+		switch token {
+		case json.Delim('{'):
+			fields := []jsonField{}
+			isStruct := t != nil && t.Kind() == reflect.Struct
+			if isStruct {
+				fields = jsonFields(t)
+			}
+
+			keys := []string{}
+			for dec.More() {
+				token, err := dec.Token()
+				if err != nil {
+					return
+				}
+				key := token.(string)
+				if hasKey(keys, key, isStruct) {
+					seq = append(seq, fmt.Sprintf("+ %s: duplicate key", jsonPointer(path, key)))
+				}
+				keys = append(keys, key)
+
+				var elem reflect.Type
+				switch {
+				case isStruct:
+					if f, ok := lookupField(fields, key); ok {
+						elem = f.typ
+					}
+				case t != nil && t.Kind() == reflect.Map:
+					elem = t.Elem()
+				}
+				walk(jsonPointer(path, key), elem)
+			}
+			dec.Token()
+		case json.Delim('['):
+			var elem reflect.Type
+			if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+				elem = t.Elem()
+			}
+			for i := 0; dec.More(); i++ {
+				walk(fmt.Sprintf("%s/%d", path, i), elem)
+			}
+			dec.Token()
+		}
+	}
+	walk("", t)
+
+	return seq
+}
+
+func hasKey(keys []string, key string, fold bool) bool {
+	for _, x := range keys {
+		if x == key || (fold && strings.EqualFold(x, key)) {
+			return true
+		}
+	}
+	return false
+}
+
+var unmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+var textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// target returns the type, which is decoded by encoding/json, it is nil for
+// types with custom decoding.
+func target(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || reflect.PtrTo(t).Implements(unmarshaler) || reflect.PtrTo(t).Implements(textUnmarshaler) {
+		return nil
+	}
+	return t
+}
+
+// fields matches the document with the type, it lists unknown fields and
+// optionally missing ones.
+func (s strict) fields(doc interface{}, t reflect.Type, path string) []string {
+	if t = target(t); t == nil {
+		return nil
+	}
+
+	seq := []string{}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(obj) {
+			f, ok := lookupField(fields, key)
+			if !ok {
+				seq = append(seq, fmt.Sprintf("+ %s: unknown field", jsonPointer(path, key)))
+				continue
+			}
+			seq = append(seq, s.fields(obj[key], f.typ, jsonPointer(path, key))...)
+		}
+
+		if s.required {
+			for _, f := range fields {
+				if _, ok := lookupKey(obj, f.name); !ok && !f.omitempty {
+					seq = append(seq, fmt.Sprintf("- %s: missing field", jsonPointer(path, f.name)))
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, x := range arr {
+			seq = append(seq, s.fields(x, t.Elem(), fmt.Sprintf("%s/%d", path, i))...)
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(obj) {
+			seq = append(seq, s.fields(obj[key], t.Elem(), jsonPointer(path, key))...)
+		}
+	}
+
+	return seq
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitempty bool
+}
+
+// jsonFields lists fields of struct as encoding/json sees them, fields of
+// embedded structs are promoted.
+func jsonFields(t reflect.Type) []jsonField {
+	seq := []jsonField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i != -1 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			seq = append(seq, jsonFields(ft)...)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		seq = append(seq, jsonField{
+			name:      name,
+			typ:       f.Type,
+			omitempty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+
+	sort.SliceStable(seq, func(i, j int) bool { return seq[i].name < seq[j].name })
+	return seq
+}
+
+// lookupField finds the field of key, encoding/json prefers exact match
+// but it also accepts case-insensitive one.
+func lookupField(fields []jsonField, key string) (jsonField, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+func lookupKey(obj map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := obj[name]; ok {
+		return v, true
+	}
+	for key, v := range obj {
+		if strings.EqualFold(key, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// jsonPointer appends the key to JSON Pointer
+func jsonPointer(path, key string) string {
+	return path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
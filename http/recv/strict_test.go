@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package recv_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+)
+
+type Base struct {
+	ID string `json:"id"`
+}
+
+type Account struct {
+	Base
+	Name    string            `json:"name"`
+	Email   string            `json:"email,omitempty"`
+	Created time.Time         `json:"created,omitempty"`
+	Tags    []Tag             `json:"tags,omitempty"`
+	Labels  map[string]Tag    `json:"labels,omitempty"`
+	Extra   interface{}       `json:"extra,omitempty"`
+	Meta    map[string]string `json:"-"`
+}
+
+type Tag struct {
+	Key string `json:"key"`
+}
+
+func recvStrict(ts string, doc string, out interface{}, opts ...ƒ.StrictOption) error {
+	req := µ.Join(
+		ø.GET(ts+"/echo"),
+		ø.Params(map[string]string{"json": doc}),
+		ƒ.Code(µ.StatusOK),
+		ƒ.RecvStrict(out, opts...),
+	)
+	return req(assay.IO(µ.Default())).Fail
+}
+
+func TestRecvStrict(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var account Account
+	err := recvStrict(ts.URL,
+		`{"id": "1", "Name": "a", "created": "2021-01-01T00:00:00Z", "tags": [{"key": "x"}], "labels": {"a": {"key": "y"}}, "extra": {"any": 1}}`,
+		&account, ƒ.RequireFields(),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.ID != "1" || account.Name != "a" || account.Tags[0].Key != "x" || account.Labels["a"].Key != "y" {
+		t.Errorf("unexpected account %+v", account)
+	}
+}
+
+func TestRecvStrictMismatch(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	for doc, diff := range map[string]string{
+		`{"id": "1", "name": "a", "nick": "b"}`:                               "+ /nick: unknown field",
+		`{"id": "1", "name": "a", "tags": [{"key": "x", "v": 1}]}`:            "+ /tags/0/v: unknown field",
+		`{"id": "1", "name": "a", "labels": {"a/b": {"k": 1}}}`:               "+ /labels/a~1b/k: unknown field\n- /labels/a~1b/key: missing field",
+		`{"id": "1", "name": "a", "id": "2"}`:                                 "+ /id: duplicate key",
+		`{"id": "1", "name": "a", "ID": "2"}`:                                 "+ /ID: duplicate key",
+		`{"id": "1", "name": "a", "labels": {"x": {"key": "a", "KEY": "b"}}}`: "+ /labels/x/KEY: duplicate key",
+		`{"id": "1", "name": "a", "tags": [{"key": "x", "key": "y"}]}`:        "+ /tags/0/key: duplicate key",
+		`{"id": "1", "name": "a"} {"id": "2"}`:                                "+ trailing data: {\"id\": \"2\"}",
+		`{"id": "1", "meta": {}}`:                                             "+ /meta: unknown field\n- /name: missing field",
+		`{"id": "1", "name": "a", "tags": [{}]}`:                              "- /tags/0/key: missing field",
+	} {
+		var account Account
+		var mismatch *assay.Mismatch
+		err := recvStrict(ts.URL, doc, &account, ƒ.RequireFields())
+		if !errors.As(err, &mismatch) || mismatch.Diff != diff {
+			t.Errorf("unexpected failure for %s: %v", doc, err)
+		}
+	}
+}
+
+func TestRecvStrictOptional(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var account Account
+	if err := recvStrict(ts.URL, `{"id": "1"}`, &account); err != nil {
+		t.Errorf("missing fields are not optional: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := recvStrict(ts.URL, `{"a": 1, "b": {"c": 2}}`, &doc); err != nil || len(doc) != 2 {
+		t.Errorf("unable to receive generic document: %v", err)
+	}
+
+	var keys map[string]interface{}
+	if err := recvStrict(ts.URL, `{"a": 1, "A": 2, "b": {"c": 3, "C": 4}}`, &keys); err != nil || len(keys) != 3 {
+		t.Errorf("keys of map are folded: %v", err)
+	}
+
+	var labeled Account
+	if err := recvStrict(ts.URL, `{"id": "1", "labels": {"x": {"key": "a"}, "X": {"key": "b"}}}`, &labeled); err != nil || len(labeled.Labels) != 2 {
+		t.Errorf("keys of map field are folded: %v", err)
+	}
+
+	var mismatch *assay.Mismatch
+	if err := recvStrict(ts.URL, `{"id": `, &account); !errors.As(err, &mismatch) {
+		t.Errorf("invalid document is accepted: %v", err)
+	}
+}
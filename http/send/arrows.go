@@ -16,6 +16,7 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/assay-it/sdk-go/assay"
 	"github.com/assay-it/sdk-go/http"
@@ -174,6 +175,18 @@ func Params(query interface{}) http.Arrow {
 
 /*
 
+Timeout limits the time of HTTP request, including reading of the response
+body. The request fails with assay.Timeout error if the limit is exceeded.
+*/
+func Timeout(timeout time.Duration) http.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		cat.HTTP.Send.Timeout = timeout
+		return cat
+	}
+}
+
+/*
+
 Send payload to destination URL. You can also use native Go data types
 (e.g. maps, struct, etc) as egress payload. The library implicitly encodes
 input structures to binary using Content-Type as a hint. The function fails
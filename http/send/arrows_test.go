@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/assay-it/sdk-go/assay"
 	"github.com/assay-it/sdk-go/http"
@@ -136,6 +137,18 @@ func TestHeaderByVal(t *testing.T) {
 	}
 }
 
+func TestTimeout(t *testing.T) {
+	req := http.Join(
+		ø.URL("GET", "https://example.com"),
+		ø.Timeout(5*time.Second),
+	)
+	cat := assay.IO(http.Default())
+
+	if cat = req(cat); cat.HTTP.Send.Timeout != 5*time.Second {
+		t.Error("unable to set timeout")
+	}
+}
+
 func TestParams(t *testing.T) {
 	type Site struct {
 		Site string `json:"site"`
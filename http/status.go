@@ -9,8 +9,11 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/assay-it/sdk-go/assay"
 )
 
 /*
@@ -165,3 +168,14 @@ const (
 	StatusNotExtended                   = StatusCode(http.StatusNotExtended)
 	StatusNetworkAuthenticationRequired = StatusCode(http.StatusNetworkAuthenticationRequired)
 )
+
+/*
+
+OnStatus matches StatusCode failures with the code, use it as a branch of
+assay.Arrow Match combinator.
+*/
+func OnStatus(code StatusCode, arrows ...assay.Arrow) assay.Case {
+	return assay.On(func(err error) bool {
+		return errors.Is(err, code)
+	}, arrows...)
+}
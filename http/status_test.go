@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/assay-it/sdk-go/assay"
 	"github.com/assay-it/sdk-go/http"
 )
 
@@ -55,3 +56,27 @@ func TestStatusCodeRequired(t *testing.T) {
 		t.Error("StatusCode invalid error")
 	}
 }
+
+func TestOnStatus(t *testing.T) {
+	var s string
+	mark := func(val string) assay.Arrow {
+		return func(cat *assay.IOCat) *assay.IOCat {
+			s = val
+			return cat
+		}
+	}
+
+	fail := func(cat *assay.IOCat) *assay.IOCat {
+		cat.Fail = http.NewStatusCode(404, http.StatusOK)
+		return cat
+	}
+
+	f := assay.Arrow(fail).Match(
+		http.OnStatus(http.StatusConflict, mark("conflict")),
+		http.OnStatus(http.StatusNotFound, mark("not found")),
+	)
+
+	if c := f(assay.IO()); c.Fail != nil || s != "not found" {
+		t.Error("unable to match status code")
+	}
+}
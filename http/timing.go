@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package http
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+// timing records phases of HTTP request using httptrace
+type timing struct {
+	sync.Mutex
+	started   time.Time
+	dnsStart  time.Time
+	dnsDone   time.Time
+	connStart time.Time
+	connDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	wrote     time.Time
+	firstByte time.Time
+}
+
+func newTiming() *timing {
+	return &timing{started: time.Now()}
+}
+
+func (t *timing) at(ts *time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	*ts = time.Now()
+}
+
+func (t *timing) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.at(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.at(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.at(&t.connStart) },
+		ConnectDone:          func(string, string, error) { t.at(&t.connDone) },
+		TLSHandshakeStart:    func() { t.at(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.at(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.at(&t.wrote) },
+		GotFirstResponseByte: func() { t.at(&t.firstByte) },
+	}
+}
+
+func (t *timing) value(done time.Time) assay.Timing {
+	t.Lock()
+	defer t.Unlock()
+
+	wrote := t.wrote
+	if wrote.IsZero() {
+		wrote = t.started
+	}
+
+	return assay.Timing{
+		DNS:     since(t.dnsStart, t.dnsDone),
+		Connect: since(t.connStart, t.connDone),
+		TLS:     since(t.tlsStart, t.tlsDone),
+		TTFB:    since(wrote, t.firstByte),
+		Total:   since(t.started, done),
+	}
+}
+
+func since(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
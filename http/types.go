@@ -9,13 +9,14 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"io"
 	"io/ioutil"
-	"log"
 	"net"
 	"net/http"
-	"net/http/httputil"
+	"net/http/httptrace"
 	"time"
 
 	"github.com/assay-it/sdk-go/assay"
@@ -33,15 +34,19 @@ Join composes HTTP arrows to high-order function
 (a ⟼ b, b ⟼ c, c ⟼ d) ⤇ a ⟼ d
 */
 func Join(arrows ...Arrow) assay.Arrow {
+	seq := make([]assay.Arrow, len(arrows))
+	for i, f := range arrows {
+		seq[i] = assay.Arrow(f)
+	}
+	join := assay.Join(seq...)
+
 	return func(cat *assay.IOCat) *assay.IOCat {
 		if cat.Fail != nil {
 			return cat
 		}
 
-		for _, f := range arrows {
-			if cat = f(cat); cat.Fail != nil {
-				return cat
-			}
+		if cat = join(cat); cat.Fail != nil {
+			return cat
 		}
 
 		if cat.HTTP != nil && cat.HTTP.Recv != nil && cat.HTTP.Recv.Response != nil {
@@ -93,6 +98,18 @@ func defaultClient() *http.Client {
 	}
 }
 
+/*
+
+Label names the step of HTTP arrow composition, see assay.Label
+  http.Join(
+    ø.GET("https://example.com"),
+    http.Label("ensure success", ƒ.Code(http.StatusOK)),
+  )
+*/
+func Label(label string, f Arrow) Arrow {
+	return Arrow(assay.Label(label, assay.Arrow(f)))
+}
+
 // DefaultIO creates default HTTP IO category
 // use for development only
 func DefaultIO(opts ...assay.Config) *assay.IOCat {
@@ -109,13 +126,36 @@ func (p pool) Unsafe(cat *assay.IOCat) *assay.IOCat {
 		return cat
 	}
 
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if cat.HTTP.Send.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(cat.Context(), cat.HTTP.Send.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(cat.Context())
+	}
+	fail := failure(cat.Context(), ctx, cat.HTTP.Send.Timeout)
+
+	timing := newTiming()
+	trace := httptrace.WithClientTrace(ctx, timing.trace())
+
+	payload, egress := cat.HTTP.Send.Payload, []byte(nil)
+	if cat.Verbosity() == assay.LogLevelDebug && payload != nil {
+		if egress, cat.Fail = ioutil.ReadAll(payload); cat.Fail != nil {
+			cancel()
+			return cat
+		}
+		payload = bytes.NewReader(egress)
+	}
+
 	var eg *http.Request
-	eg, cat.Fail = http.NewRequest(
+	eg, cat.Fail = http.NewRequestWithContext(
+		trace,
 		cat.HTTP.Send.Method,
 		cat.HTTP.Send.URL.String(),
-		cat.HTTP.Send.Payload,
+		payload,
 	)
 	if cat.Fail != nil {
+		cancel()
 		return cat
 	}
 
@@ -125,30 +165,116 @@ func (p pool) Unsafe(cat *assay.IOCat) *assay.IOCat {
 
 	var in *http.Response
 	in, cat.Fail = p.Client.Do(eg)
+	done := time.Now()
+
+	logSend(cat, eg, egress)
+
 	if cat.Fail != nil {
+		cancel()
+		cat.Fail = fail(cat.Fail)
 		return cat
 	}
 
-	cat.HTTP.Recv = &assay.DnStreamHTTP{Response: in}
+	var ingress []byte
+	if cat.Verbosity() == assay.LogLevelDebug {
+		ingress, cat.Fail = ioutil.ReadAll(in.Body)
+		in.Body.Close()
+		if cat.Fail != nil {
+			cancel()
+			cat.Fail = fail(cat.Fail)
+			return cat
+		}
+		in.Body = ioutil.NopCloser(bytes.NewReader(ingress))
+	}
+
+	// Note: the context of request is released only when response body is
+	//       closed, otherwise the body would not be readable by arrows.
+	in.Body = &body{ReadCloser: in.Body, cancel: cancel, fail: fail}
+	cat.HTTP.Recv = &assay.DnStreamHTTP{
+		Response: in,
+		Code:     in.StatusCode,
+		Timing:   timing.value(done),
+	}
 
-	logSend(cat.LogLevel, eg)
-	logRecv(cat.LogLevel, in)
+	logRecv(cat, eg, in, ingress, cat.HTTP.Recv.Timing.Total)
 
 	return cat
 }
 
-func logSend(level int, eg *http.Request) {
-	if level >= assay.LogLevelEgress {
-		if msg, err := httputil.DumpRequest(eg, level == assay.LogLevelDebug); err == nil {
-			log.Printf(">>>>\n%s\n", msg)
+// failure maps errors caused by the context of request to assay failures
+func failure(parent, ctx context.Context, timeout time.Duration) func(error) error {
+	return func(err error) error {
+		switch {
+		case parent.Err() != nil:
+			return &assay.Canceled{Err: parent.Err()}
+		case ctx.Err() == context.DeadlineExceeded:
+			return &assay.Timeout{Duration: timeout}
 		}
+		return err
 	}
 }
 
-func logRecv(level int, in *http.Response) {
-	if level >= assay.LogLevelIngress {
-		if msg, err := httputil.DumpResponse(in, level == assay.LogLevelDebug); err == nil {
-			log.Printf("<<<<\n%s\n", msg)
-		}
+// body releases the context of request when response is consumed, failures
+// of reading caused by the context are reported as assay failures.
+type body struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	fail   func(error) error
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.fail(err)
+	}
+	return n, err
+}
+
+func (b *body) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func logSend(cat *assay.IOCat, eg *http.Request, payload []byte) {
+	if cat.Verbosity() < assay.LogLevelEgress {
+		return
+	}
+
+	size := eg.ContentLength
+	if payload != nil {
+		size = int64(len(payload))
+	}
+
+	cat.Log(&assay.Event{
+		Type:   assay.EventEgress,
+		Method: eg.Method,
+		URL:    eg.URL,
+		Proto:  eg.Proto,
+		Size:   size,
+		Header: eg.Header.Clone(),
+		Body:   payload,
+	})
+}
+
+func logRecv(cat *assay.IOCat, eg *http.Request, in *http.Response, payload []byte, duration time.Duration) {
+	if cat.Verbosity() < assay.LogLevelIngress {
+		return
 	}
+
+	size := in.ContentLength
+	if payload != nil {
+		size = int64(len(payload))
+	}
+
+	cat.Log(&assay.Event{
+		Type:     assay.EventIngress,
+		Method:   eg.Method,
+		URL:      eg.URL,
+		Proto:    in.Proto,
+		Status:   in.StatusCode,
+		Duration: duration,
+		Size:     size,
+		Header:   in.Header.Clone(),
+		Body:     payload,
+	})
 }
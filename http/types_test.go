@@ -9,9 +9,13 @@
 package http_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/assay-it/sdk-go/assay"
 	µ "github.com/assay-it/sdk-go/http"
@@ -55,13 +59,175 @@ func TestJoinAssay(t *testing.T) {
 	}
 }
 
+func TestJoinTrace(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := µ.Join(
+		ø.URL("GET", ts.URL+"/ok"),
+		ƒ.Code(µ.StatusOK),
+		µ.Label("ensure json", ƒ.Header("Content-Type").Is("application/json")),
+	)
+
+	cat := assay.IO(µ.Default(), assay.Tracing())
+	cat = req(cat)
+
+	step, ok := cat.Trace().Failed()
+	if !ok || len(cat.Trace()) != 3 ||
+		!labelled(cat.Trace()[0].Label, "send.URL") ||
+		!labelled(cat.Trace()[1].Label, "recv.Code") ||
+		step.Index != 3 || step.Label != "ensure json" {
+		t.Errorf("unexpected steps %v", cat.Trace())
+	}
+}
+
+func TestJoinContext(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := µ.Join(
+		ø.URL("GET", ts.URL+"/slow"),
+		ƒ.Code(µ.StatusOK),
+	)
+
+	cat := assay.IO(µ.Default(), assay.Context(ctx))
+	cat = req(cat)
+
+	var e *assay.Canceled
+	if !errors.As(cat.Fail, &e) || !errors.Is(cat.Fail, context.DeadlineExceeded) {
+		t.Error("http.Join is not canceled")
+	}
+}
+
+func TestJoinTimeout(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := µ.Join(
+		ø.URL("GET", ts.URL+"/slow"),
+		ø.Timeout(10*time.Millisecond),
+		ƒ.Code(µ.StatusOK),
+	)
+
+	cat := assay.IO(µ.Default())
+	cat = req(cat)
+
+	var e *assay.Timeout
+	if !errors.As(cat.Fail, &e) || e.Duration != 10*time.Millisecond {
+		t.Error("http.Join is not timed out")
+	}
+}
+
+func TestJoinTimeoutBody(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var data []byte
+	req := µ.Join(
+		ø.URL("GET", ts.URL+"/stream"),
+		ø.Timeout(10*time.Millisecond),
+		ƒ.Code(µ.StatusOK),
+		ƒ.Bytes(&data),
+	)
+
+	cat := assay.IO(µ.Default())
+	cat = req(cat)
+
+	var e *assay.Timeout
+	if !errors.As(cat.Fail, &e) || e.Duration != 10*time.Millisecond {
+		t.Error("reading of body is not timed out")
+	}
+}
+
+func TestJoinRetry(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := assay.Retry(
+		assay.Policy{Attempts: 5},
+		µ.Join(
+			ø.URL("GET", ts.URL+"/flaky"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	if cat = req(cat); cat.Fail != nil {
+		t.Error("http.Join is not retried")
+	}
+}
+
+func TestLogWith(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	seq := []*assay.Event{}
+	logger := assay.LoggerFunc(func(e *assay.Event) { seq = append(seq, e) })
+
+	var data []byte
+	req := µ.Join(
+		ø.URL("POST", ts.URL+"/ok"),
+		ø.ContentJSON(),
+		ø.Send(map[string]string{"a": "b"}),
+		ƒ.Code(µ.StatusOK),
+		ƒ.Bytes(&data),
+	)
+
+	cat := assay.IO(µ.Default(), assay.Logging(assay.LogLevelDebug), assay.LogWith(logger))
+	if cat = req(cat); cat.Fail != nil {
+		t.Error("http.Join failed")
+	}
+
+	if len(seq) != 2 || seq[0].Type != assay.EventEgress || seq[1].Type != assay.EventIngress {
+		t.Fatal("traffic is not logged")
+	}
+
+	if seq[0].Method != "POST" || string(seq[0].Body) != `{"a":"b"}` || seq[0].Size != 9 {
+		t.Error("egress event is invalid")
+	}
+
+	if seq[1].Status != 200 || string(seq[1].Body) != "ok" || seq[1].Duration == 0 {
+		t.Error("ingress event is invalid")
+	}
+
+	if string(data) != "ok" {
+		t.Error("logging consumes response")
+	}
+}
+
+// labelled matches the label of step, toolchains before Go 1.27 prefix
+// closures of inlined functions with the caller, e.g. pkg.TestX.identity
+func labelled(label, expect string) bool {
+	name := expect[strings.LastIndex(expect, ".")+1:]
+	return label == expect || strings.HasSuffix(label, "."+name)
+}
+
 //
 func mock() *httptest.Server {
+	flaky := 0
 	return httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			switch {
 			case r.URL.Path == "/ok":
 				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("ok"))
+			case r.URL.Path == "/flaky":
+				if flaky++; flaky < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			case r.URL.Path == "/slow":
+				time.Sleep(100 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			case r.URL.Path == "/stream":
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				time.Sleep(100 * time.Millisecond)
+				w.Write([]byte("ok"))
 			default:
 				w.WriteHeader(http.StatusBadRequest)
 			}
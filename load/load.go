@@ -0,0 +1,212 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package load runs Behavior as a Code scenarios as a load test. The runner
+spawns workers, each worker evaluates the scenario in the loop using own
+I/O category. The load profile is defined by stages, the number of workers
+and the rate are linearly ramped from previous stage to the target of next.
+
+  report := load.Run(context.Background(),
+    []load.Stage{
+      {Duration: 10 * time.Second, Workers: 20, Rate: 100},  // ramp-up
+      {Duration: 60 * time.Second, Workers: 20, Rate: 100},  // sustain
+    },
+    TestScenario,
+    http.Stack(client),
+  )
+
+*/
+package load
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/slo"
+)
+
+/*
+
+Stage of load profile. Workers is the number of concurrent workers and Rate
+is a target number of scenarios per second at the end of stage. Rate zero
+means unlimited rate, workers evaluate scenarios back-to-back.
+*/
+type Stage struct {
+	Duration time.Duration
+	Workers  int
+	Rate     float64
+}
+
+/*
+
+Report of load test, it extends slo.Report with achieved rate of scenarios
+per second.
+*/
+type Report struct {
+	slo.Report
+	Rate float64
+}
+
+// profile interpolates stages at given time
+type profile []Stage
+
+func (p profile) at(elapsed time.Duration) (workers int, rate float64, done bool) {
+	prevWorkers, prevRate := 0, 0.0
+	for _, stage := range p {
+		if elapsed < stage.Duration {
+			f := float64(elapsed) / float64(stage.Duration)
+			workers = prevWorkers + int(math.Round(f*float64(stage.Workers-prevWorkers)))
+			if workers < 1 && stage.Workers > 0 {
+				workers = 1
+			}
+
+			if stage.Rate > 0 {
+				rate = prevRate + f*(stage.Rate-prevRate)
+			}
+			return workers, rate, false
+		}
+
+		elapsed -= stage.Duration
+		prevWorkers, prevRate = stage.Workers, stage.Rate
+	}
+	return 0, 0, true
+}
+
+func (p profile) workers() int {
+	n := 0
+	for _, stage := range p {
+		if stage.Workers > n {
+			n = stage.Workers
+		}
+	}
+	return n
+}
+
+// state of the runner shared by workers
+type state struct {
+	sync.RWMutex
+	workers int
+	rate    float64
+	done    bool
+}
+
+func (s *state) get() (int, float64, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.workers, s.rate, s.done
+}
+
+func (s *state) set(workers int, rate float64, done bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.workers, s.rate, s.done = workers, rate, done
+}
+
+const tick = 5 * time.Millisecond
+
+/*
+
+Run executes load test of the scenario. Each worker creates own instance
+of scenario and own I/O category configured with given options, use
+http.Stack config to share HTTP client pool across workers. The function
+returns when all stages are completed or the context is done.
+*/
+func Run(ctx context.Context, stages []Stage, scenario func() assay.Arrow, opts ...assay.Config) Report {
+	p := profile(stages)
+	sampler := slo.NewSampler()
+	tokens := make(chan struct{}, p.workers())
+	st := &state{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers(); i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			worker(ctx, id, st, tokens, sampler, scenario, opts)
+		}(i)
+	}
+
+	started, last := time.Now(), time.Now()
+	credit := 0.0
+	for {
+		now := time.Now()
+		workers, rate, done := p.at(now.Sub(started))
+		if ctx.Err() != nil {
+			done = true
+		}
+		st.set(workers, rate, done)
+		if done {
+			break
+		}
+
+		if rate > 0 {
+			credit += rate * now.Sub(last).Seconds()
+			for ; credit >= 1; credit-- {
+				select {
+				case tokens <- struct{}{}:
+				default:
+				}
+			}
+		}
+		last = now
+		time.Sleep(tick)
+	}
+	wg.Wait()
+
+	report := Report{Report: sampler.Report()}
+	if report.Elapsed > 0 {
+		report.Rate = float64(report.Runs) / report.Elapsed.Seconds()
+	}
+	return report
+}
+
+func worker(
+	ctx context.Context,
+	id int,
+	st *state,
+	tokens <-chan struct{},
+	sampler *slo.Sampler,
+	scenario func() assay.Arrow,
+	opts []assay.Config,
+) {
+	cat := assay.IO(append(append([]assay.Config{}, opts...), assay.Context(ctx))...)
+	f := scenario()
+
+	for {
+		workers, rate, done := st.get()
+		switch {
+		case done:
+			return
+		case id >= workers:
+			time.Sleep(tick)
+			continue
+		case rate > 0:
+			select {
+			case <-tokens:
+			case <-time.After(tick):
+				continue
+			}
+		}
+
+		t := time.Now()
+		cat = f(cat)
+
+		var canceled *assay.Canceled
+		if !errors.As(cat.Fail, &canceled) {
+			sampler.Sample(time.Since(t), cat)
+		}
+		cat.Recover()
+		cat.Reset()
+	}
+}
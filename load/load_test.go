@@ -0,0 +1,116 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package load_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+	"github.com/assay-it/sdk-go/load"
+)
+
+func scenario(url string) func() assay.Arrow {
+	return func() assay.Arrow {
+		return µ.Join(
+			ø.GET(url),
+			ƒ.Code(µ.StatusOK),
+		)
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	report := load.Run(context.Background(),
+		[]load.Stage{
+			{Duration: 100 * time.Millisecond, Workers: 4, Rate: 50},
+			{Duration: 300 * time.Millisecond, Workers: 4, Rate: 50},
+		},
+		scenario(ts.URL+"/ok"),
+		µ.Default(),
+	)
+
+	if report.Runs < 12 || report.Runs > 24 || report.Status[200] != report.Runs || report.Failures != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestRunUnlimited(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	report := load.Run(context.Background(),
+		[]load.Stage{
+			{Duration: 50 * time.Millisecond, Workers: 2},
+			{Duration: 50 * time.Millisecond, Workers: 2},
+		},
+		scenario(ts.URL+"/ok"),
+		µ.Default(),
+	)
+
+	if report.Runs < 10 || report.Rate == 0 || report.P99 == 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	report := load.Run(context.Background(),
+		[]load.Stage{{Duration: 50 * time.Millisecond, Workers: 1}},
+		scenario(ts.URL+"/fail"),
+		µ.Default(),
+	)
+
+	if report.Runs == 0 || report.Failures != report.Runs || report.Status[503] != report.Runs {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	t0 := time.Now()
+	load.Run(ctx,
+		[]load.Stage{{Duration: time.Minute, Workers: 2}},
+		scenario(ts.URL+"/ok"),
+		µ.Default(),
+	)
+
+	if time.Since(t0) > time.Second {
+		t.Error("load test is not canceled")
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/ok":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}),
+	)
+}
@@ -0,0 +1,264 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package slo asserts service level objectives (latency percentiles and error
+rate) over repeated runs of Behavior as a Code scenarios.
+
+  func TestLatency() assay.Arrow {
+    var report slo.Report
+    return slo.Check(
+      slo.Objective{
+        Runs:      100,
+        P95:       200 * time.Millisecond,
+        ErrorRate: 0.01,
+      },
+      &report,
+      http.Join(
+        ø.GET("https://example.com"),
+        ƒ.Code(http.StatusOK),
+      ),
+    )
+  }
+
+*/
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+)
+
+/*
+
+Objective defines parameters of the check. The scenario is evaluated either
+given number of Runs or for the Duration. Zero values of percentiles are
+not asserted. ErrorRate is maximum allowed fraction of failed runs.
+*/
+type Objective struct {
+	Runs      int
+	Duration  time.Duration
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	ErrorRate float64
+}
+
+/*
+
+Report of runs. Status is the histogram of HTTP status codes, the code is
+either received by successful run or reported by http.StatusCode failure.
+Other failures are counted with code 0.
+*/
+type Report struct {
+	Runs      int
+	Failures  int
+	ErrorRate float64
+	Min       time.Duration
+	Max       time.Duration
+	Mean      time.Duration
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Elapsed   time.Duration
+	Status    map[int]int
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf("runs: %d, failures: %d (%.2f%%), p50: %v, p95: %v, p99: %v",
+		r.Runs, r.Failures, 100*r.ErrorRate, r.P50, r.P95, r.P99)
+}
+
+/*
+
+Sampler accumulates outcomes of runs and builds the report, it is safe for
+concurrent use.
+*/
+type Sampler struct {
+	sync.Mutex
+	started time.Time
+	samples []time.Duration
+	report  Report
+}
+
+// NewSampler creates new sampler
+func NewSampler() *Sampler {
+	return &Sampler{
+		started: time.Now(),
+		report:  Report{Status: map[int]int{}},
+	}
+}
+
+/*
+
+Sample records outcome of the run: the latency and the category after
+evaluation of scenario.
+*/
+func (s *Sampler) Sample(latency time.Duration, cat *assay.IOCat) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.samples = append(s.samples, latency)
+	s.report.Runs++
+	s.report.Status[code(cat)]++
+
+	if cat.Fail != nil {
+		s.report.Failures++
+	}
+}
+
+func code(cat *assay.IOCat) int {
+	var status http.StatusCode
+	switch {
+	case errors.As(cat.Fail, &status):
+		return status.Value()
+	case cat.Fail != nil:
+		return 0
+	case cat.HTTP != nil && cat.HTTP.Recv != nil:
+		return cat.HTTP.Recv.Code
+	default:
+		return 0
+	}
+}
+
+// Report builds report from recorded samples
+func (s *Sampler) Report() Report {
+	s.Lock()
+	defer s.Unlock()
+
+	r := s.report
+	r.Elapsed = time.Since(s.started)
+	r.Status = make(map[int]int, len(s.report.Status))
+	for code, n := range s.report.Status {
+		r.Status[code] = n
+	}
+
+	if r.Runs == 0 {
+		return r
+	}
+
+	seq := make([]time.Duration, len(s.samples))
+	copy(seq, s.samples)
+	sort.Slice(seq, func(i, j int) bool { return seq[i] < seq[j] })
+
+	var sum time.Duration
+	for _, x := range seq {
+		sum += x
+	}
+
+	r.ErrorRate = float64(r.Failures) / float64(r.Runs)
+	r.Min, r.Max = seq[0], seq[len(seq)-1]
+	r.Mean = sum / time.Duration(len(seq))
+	r.P50 = Percentile(seq, 50)
+	r.P95 = Percentile(seq, 95)
+	r.P99 = Percentile(seq, 99)
+	return r
+}
+
+/*
+
+Percentile returns nearest-rank percentile of sorted sequence
+*/
+func Percentile(seq []time.Duration, p float64) time.Duration {
+	if len(seq) == 0 {
+		return 0
+	}
+
+	rank := int(p/100*float64(len(seq))+0.5) - 1
+	switch {
+	case rank < 0:
+		rank = 0
+	case rank >= len(seq):
+		rank = len(seq) - 1
+	}
+	return seq[rank]
+}
+
+/*
+
+Check evaluates the scenario repeatedly and asserts the objective. Each run
+is applied to own fork of category. The evaluation fails with assay.Mismatch
+if objective is not met, the payload of failure is the report. The report is
+copied to the variable, supply the pointer to it or nil if it is not needed.
+*/
+func Check(objective Objective, report *Report, arrow assay.Arrow) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		sampler := NewSampler()
+		deadline := time.Now().Add(objective.Duration)
+
+		for i := 0; objective.continues(i, deadline); i++ {
+			if cat.Done() {
+				return cat
+			}
+
+			t := time.Now()
+			fork := arrow(cat.Fork())
+			sampler.Sample(time.Since(t), fork)
+			fork.Reset()
+		}
+
+		result := sampler.Report()
+		if report != nil {
+			*report = result
+		}
+
+		if diff := objective.diff(&result); diff != "" {
+			cat.Fail = &assay.Mismatch{Diff: diff, Payload: result}
+		}
+		return cat
+	}
+}
+
+func (objective Objective) continues(run int, deadline time.Time) bool {
+	if objective.Runs <= 0 && objective.Duration <= 0 {
+		return run < 1
+	}
+
+	if objective.Runs > 0 && run >= objective.Runs {
+		return false
+	}
+
+	if objective.Duration > 0 && !time.Now().Before(deadline) {
+		return false
+	}
+
+	return true
+}
+
+func (objective Objective) diff(r *Report) string {
+	diff := []string{}
+
+	for _, x := range []struct {
+		metric         string
+		budget, actual time.Duration
+	}{
+		{"p50", objective.P50, r.P50},
+		{"p95", objective.P95, r.P95},
+		{"p99", objective.P99, r.P99},
+	} {
+		if x.budget > 0 && x.actual > x.budget {
+			diff = append(diff,
+				fmt.Sprintf("- %s: %v\n+ %s: %v", x.metric, x.budget, x.metric, x.actual))
+		}
+	}
+
+	if r.ErrorRate > objective.ErrorRate {
+		diff = append(diff,
+			fmt.Sprintf("- error rate: %.4f\n+ error rate: %.4f", objective.ErrorRate, r.ErrorRate))
+	}
+
+	return strings.Join(diff, "\n")
+}
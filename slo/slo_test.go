@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package slo_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+	"github.com/assay-it/sdk-go/slo"
+)
+
+func TestCheck(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var report slo.Report
+	req := slo.Check(
+		slo.Objective{Runs: 20, P95: time.Second},
+		&report,
+		µ.Join(
+			ø.GET(ts.URL+"/ok"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	if cat = req(cat); cat.Fail != nil {
+		t.Errorf("slo is failed: %v", cat.Fail)
+	}
+
+	if report.Runs != 20 || report.Failures != 0 || report.Status[200] != 20 || report.P95 == 0 {
+		t.Errorf("unexpected report %v", report)
+	}
+}
+
+func TestCheckDuration(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var report slo.Report
+	req := slo.Check(
+		slo.Objective{Duration: 50 * time.Millisecond},
+		&report,
+		µ.Join(
+			ø.GET(ts.URL+"/ok"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	if cat = req(cat); cat.Fail != nil || report.Runs < 2 || report.Elapsed < 50*time.Millisecond {
+		t.Errorf("unexpected report %v", report)
+	}
+}
+
+func TestCheckErrorRate(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var report slo.Report
+	req := slo.Check(
+		slo.Objective{Runs: 10, ErrorRate: 0.2},
+		&report,
+		µ.Join(
+			ø.GET(ts.URL+"/flaky"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	cat = req(cat)
+
+	var e *assay.Mismatch
+	if !errors.As(cat.Fail, &e) {
+		t.Error("error rate is not asserted")
+	}
+
+	if report.Failures != 5 || report.ErrorRate != 0.5 || report.Status[503] != 5 || report.Status[200] != 5 {
+		t.Errorf("unexpected report %v", report)
+	}
+}
+
+func TestCheckNoReport(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	req := slo.Check(
+		slo.Objective{Runs: 10, ErrorRate: 0.2},
+		nil,
+		µ.Join(
+			ø.GET(ts.URL+"/flaky"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	cat = req(cat)
+
+	var e *assay.Mismatch
+	if !errors.As(cat.Fail, &e) {
+		t.Fatal("error rate is not asserted")
+	}
+
+	if report, ok := e.Payload.(slo.Report); !ok || report.Failures != 5 {
+		t.Errorf("unexpected payload %v", e.Payload)
+	}
+}
+
+func TestCheckLatency(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+
+	var report slo.Report
+	req := slo.Check(
+		slo.Objective{Runs: 3, P50: time.Millisecond},
+		&report,
+		µ.Join(
+			ø.GET(ts.URL+"/slow"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := assay.IO(µ.Default())
+	cat = req(cat)
+
+	var e *assay.Mismatch
+	if !errors.As(cat.Fail, &e) || report.P50 < 10*time.Millisecond {
+		t.Error("latency is not asserted")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	seq := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		seq = append(seq, time.Duration(i))
+	}
+
+	for p, expect := range map[float64]time.Duration{0: 1, 50: 50, 95: 95, 99: 99, 100: 100} {
+		if v := slo.Percentile(seq, p); v != expect {
+			t.Errorf("unexpected percentile p%v: %v", p, v)
+		}
+	}
+
+	if slo.Percentile(nil, 50) != 0 {
+		t.Error("unexpected percentile of empty sequence")
+	}
+}
+
+//
+func mock() *httptest.Server {
+	flaky := 0
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/ok":
+				w.WriteHeader(http.StatusOK)
+			case r.URL.Path == "/slow":
+				time.Sleep(10 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			case r.URL.Path == "/flaky":
+				if flaky++; flaky%2 == 0 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}),
+	)
+}
@@ -0,0 +1,247 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+/*
+
+ReadRecords reads the JSON Lines run file written by JSONLines reporter
+*/
+func ReadRecords(r io.Reader) ([]Record, error) {
+	seq := []Record{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid record at line %d: %w", line, err)
+		}
+		seq = append(seq, rec)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return seq, nil
+}
+
+/*
+
+HTML reports results as self-contained HTML page
+*/
+func HTML(w io.Writer) Reporter {
+	return ReporterFunc(func(results []Result) error {
+		seq := make([]Record, len(results))
+		for i, r := range results {
+			seq[i] = r.Record()
+		}
+		return WriteHTML(w, seq)
+	})
+}
+
+/*
+
+WriteHTML renders recorded run as self-contained HTML page. The page shows
+status of each scenario, the failure with diff, steps of arrow composition
+and timeline of HTTP exchanges with its traffic. Use it for offline triage of failures
+  seq, err := suite.ReadRecords(file)
+  suite.WriteHTML(w, seq)
+*/
+func WriteHTML(w io.Writer, records []Record) error {
+	page := htmlPage{Scenarios: make([]htmlScenario, len(records))}
+	for i, rec := range records {
+		page.Total += rec.Duration
+		if rec.Status != "pass" {
+			page.Failed++
+		}
+		page.Scenarios[i] = newHTMLScenario(rec)
+	}
+
+	// failed scenarios are shown first
+	sort.SliceStable(page.Scenarios, func(i, j int) bool {
+		return page.Scenarios[i].Status != "pass" && page.Scenarios[j].Status == "pass"
+	})
+
+	return htmlReport.Execute(w, page)
+}
+
+type htmlPage struct {
+	Scenarios []htmlScenario
+	Failed    int
+	Total     float64
+}
+
+type htmlScenario struct {
+	Record
+	Exchanges []htmlExchange
+}
+
+type htmlExchange struct {
+	Method   string
+	URL      string
+	Status   int
+	Duration float64
+	Width    float64
+	Open     bool
+	Request  *Message
+	Response *Message
+}
+
+func newHTMLScenario(rec Record) htmlScenario {
+	seq := []htmlExchange{}
+	for i := range rec.Traffic {
+		msg := &rec.Traffic[i]
+		switch msg.Type {
+		case assay.EventEgress:
+			seq = append(seq, htmlExchange{Method: msg.Method, URL: msg.URL, Request: msg})
+		case assay.EventIngress:
+			if len(seq) == 0 || seq[len(seq)-1].Response != nil {
+				seq = append(seq, htmlExchange{Method: msg.Method, URL: msg.URL})
+			}
+			x := &seq[len(seq)-1]
+			x.Response, x.Status, x.Duration = msg, msg.Status, msg.Duration
+		}
+	}
+
+	for i := range seq {
+		if rec.Duration > 0 {
+			seq[i].Width = 100 * seq[i].Duration / rec.Duration
+		}
+		if seq[i].Width < 1 {
+			seq[i].Width = 1
+		}
+	}
+
+	// the last exchange of failed scenario is expanded
+	if rec.Status != "pass" && len(seq) > 0 {
+		seq[len(seq)-1].Open = true
+	}
+
+	return htmlScenario{Record: rec, Exchanges: seq}
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": func(sec float64) string {
+		return time.Duration(sec * float64(time.Second)).Round(time.Microsecond).String()
+	},
+	"class": func(code int) string {
+		switch {
+		case code == 0:
+			return "none"
+		case code < 400:
+			return "pass"
+		default:
+			return "fail"
+		}
+	},
+	"message": func(msg *Message) string {
+		buf := &strings.Builder{}
+		keys := make([]string, 0, len(msg.Header))
+		for key := range msg.Header {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, val := range msg.Header[key] {
+				fmt.Fprintf(buf, "%s: %s\n", key, val)
+			}
+		}
+		if msg.Body != "" {
+			fmt.Fprintf(buf, "\n%s", msg.Body)
+		}
+		return buf.String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>assay report</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+pre { background: #f6f8fa; padding: .8em; overflow-x: auto; font-size: .85em; }
+details { margin: .4em 0; }
+summary { cursor: pointer; }
+.scenario { border-left: 4px solid #2da44e; padding: .2em 1em; margin: 1em 0; }
+.scenario.fail { border-color: #cf222e; }
+.badge { display: inline-block; min-width: 3em; text-align: center; border-radius: 3px; color: #fff; font-size: .8em; padding: 0 .4em; }
+.pass .badge, .badge.pass { background: #2da44e; }
+.fail .badge, .badge.fail { background: #cf222e; }
+.badge.none { background: #6e7781; }
+.error { color: #cf222e; }
+.meta { color: #57606a; font-size: .85em; }
+.steps { list-style: none; padding-left: 0; font-size: .9em; }
+.steps .fail { color: #cf222e; }
+.tag { background: #ddf4ff; border-radius: 3px; padding: 0 .4em; }
+.bar { display: inline-block; height: .6em; background: #0969da; vertical-align: middle; }
+.timeline { width: 30em; display: inline-block; }
+</style>
+</head>
+<body>
+<h1>{{len .Scenarios}} scenarios, {{.Failed}} failed ({{duration .Total}})</h1>
+{{range .Scenarios}}
+<div class="scenario {{.Status}}">
+  <h2><span class="badge">{{.Status}}</span> {{.Name}} <small>{{duration .Duration}}</small></h2>
+  {{if or .Tags .Owner}}<p class="meta">{{range .Tags}}<span class="tag">{{.}}</span> {{end}}{{with .Owner}}owner: {{.}}{{end}}</p>{{end}}
+  {{with .Description}}<p>{{.}}</p>{{end}}
+  {{if .Error}}
+  <p class="error">{{.Type}}</p>
+  <pre>{{.Error}}</pre>
+  {{end}}
+  {{if .Diff}}
+  <details open><summary>diff</summary><pre>{{.Diff}}</pre></details>
+  {{end}}
+  {{if .Steps}}
+  <details{{if ne .Status "pass"}} open{{end}}><summary>steps</summary>
+    <ol class="steps">
+    {{range .Steps}}
+      <li class="{{if .Error}}fail{{else}}pass{{end}}" style="margin-left: {{.Depth}}em">
+        {{.Index}}. {{.Label}} <small>{{duration .Duration}}</small>
+        {{with .Error}}<span class="error">{{.}}</span>{{end}}
+      </li>
+    {{end}}
+    </ol>
+  </details>
+  {{end}}
+  {{range .Exchanges}}
+  <details{{if .Open}} open{{end}}>
+    <summary>
+      <span class="badge {{class .Status}}">{{if .Status}}{{.Status}}{{else}}---{{end}}</span>
+      {{.Method}} {{.URL}}
+      <span class="timeline"><span class="bar" style="width: {{printf "%.1f" .Width}}%"></span></span>
+      {{duration .Duration}}
+    </summary>
+    {{with .Request}}<pre>&gt;&gt;&gt;&gt; {{.Method}} {{.URL}}
+{{message .}}</pre>{{end}}
+    {{with .Response}}<pre>&lt;&lt;&lt;&lt; {{.Status}}
+{{message .}}</pre>{{end}}
+  </details>
+  {{end}}
+</div>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func TestReadRecords(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := suite.JSONLines(buf).Report(results(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	seq, err := suite.ReadRecords(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seq) != 2 || seq[0].Name != "Ok" || seq[1].Name != "NotFound" || len(seq[1].Traffic) != 2 {
+		t.Errorf("unexpected records %+v", seq)
+	}
+
+	if _, err := suite.ReadRecords(strings.NewReader("{}\nnot json\n")); err == nil {
+		t.Error("invalid run file is accepted")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := suite.HTML(buf).Report(results(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, expect := range []string{
+		"2 scenarios, 1 failed",
+		"NotFound",
+		"http.StatusCode",
+		`<span class="badge fail">404</span>`,
+		`<span class="badge pass">200</span>`,
+		"&gt;&gt;&gt;&gt; GET",
+		"2. recv.Code",
+	} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("report do not contain %s", expect)
+		}
+	}
+
+	if strings.Index(out, "NotFound") > strings.Index(out, "Ok") {
+		t.Error("failed scenarios are not shown first")
+	}
+}
+
+func TestWriteHTMLEscape(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := suite.WriteHTML(buf, []suite.Record{
+		{
+			Name:   "<script>",
+			Status: "fail",
+			Diff:   "- a: <b>",
+			Traffic: []suite.Message{
+				{Type: assay.EventIngress, Method: "GET", URL: "http://example.com", Status: 500},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "<script>") || !strings.Contains(out, "- a: &lt;b&gt;") ||
+		!strings.Contains(out, `<span class="badge fail">500</span>`) {
+		t.Errorf("unexpected report %s", out)
+	}
+}
@@ -0,0 +1,214 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/http"
+)
+
+/*
+
+Options of standalone runner
+*/
+type Options struct {
+	Target   string
+	LogLevel string
+	Run      string
+	Tags     string
+	Parallel int
+	Format   string
+	Timeout  time.Duration
+}
+
+/*
+
+Flags defines command line flags of the runner at the flag set
+*/
+func (opts *Options) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&opts.Target, "target", "", "target host of suite, it is exported as BUILD_ENDPOINT for assay.Host")
+	fs.StringVar(&opts.LogLevel, "log", "none", "log level of I/O traffic: none, egress, ingress or debug")
+	fs.StringVar(&opts.Run, "run", "", "run only scenarios matching the regular expression")
+	fs.StringVar(&opts.Tags, "tags", "", "run only scenarios matching the tag expression, e.g. \"smoke && !destructive\"")
+	fs.IntVar(&opts.Parallel, "parallel", 1, "number of scenarios to run concurrently")
+	fs.StringVar(&opts.Format, "format", "text", "output format: text, json, junit, tap or html")
+	fs.DurationVar(&opts.Timeout, "timeout", 10*time.Minute, "timeout of suite")
+}
+
+func (opts *Options) logLevel() (int, error) {
+	switch strings.ToLower(opts.LogLevel) {
+	case "", "none":
+		return assay.LogLevelNone, nil
+	case "egress":
+		return assay.LogLevelEgress, nil
+	case "ingress":
+		return assay.LogLevelIngress, nil
+	case "debug":
+		return assay.LogLevelDebug, nil
+	}
+
+	level, err := strconv.Atoi(opts.LogLevel)
+	if err != nil || level < assay.LogLevelNone || level > assay.LogLevelDebug {
+		return 0, fmt.Errorf("unknown log level %s", opts.LogLevel)
+	}
+	return level, nil
+}
+
+/*
+
+Exec runs scenarios chosen by the selector, up to parallel scenarios are
+executed concurrently. Results are returned in the order of registration,
+the traffic of results includes content of packets.
+*/
+func (s *Suite) Exec(ctx context.Context, selector Selector, parallel int, opts ...assay.Config) []Result {
+	return s.execute(ctx, selector, parallel, assay.LogLevelDebug, opts...)
+}
+
+func (s *Suite) execute(ctx context.Context, selector Selector, parallel, level int, opts ...assay.Config) []Result {
+	seq := []Scenario{}
+	for _, scenario := range s.Scenarios() {
+		if selector == nil || selector(scenario) {
+			seq = append(seq, scenario)
+		}
+	}
+
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	results := make([]Result, len(seq))
+	slots := make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+	for i, scenario := range seq {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, scenario Scenario) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i] = s.exec(scenario, level, append(append([]assay.Config{}, opts...), assay.Context(ctx))...)
+		}(i, scenario)
+	}
+	wg.Wait()
+
+	return results
+}
+
+/*
+
+Main is an entry point of compiled suite, it runs scenarios of the default
+suite with command line options and exits non-zero if any scenario fails.
+The runner uses http.Default stack unless other is defined by configs.
+*/
+func Main(opts ...assay.Config) {
+	os.Exit(std.Main(os.Args[1:], os.Stdout, opts...))
+}
+
+/*
+
+Main runs scenarios of suite with command line arguments and writes results
+to the writer. It returns exit code of the runner.
+*/
+func (s *Suite) Main(args []string, w io.Writer, opts ...assay.Config) int {
+	fs := flag.NewFlagSet("assay", flag.ContinueOnError)
+	fs.SetOutput(w)
+
+	var options Options
+	options.Flags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if reporter(options.Format, w) == nil {
+		fmt.Fprintf(w, "unknown format %s\n", options.Format)
+		return 2
+	}
+
+	level, err := options.logLevel()
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 2
+	}
+
+	var named Selector
+	if options.Run != "" {
+		re, err := regexp.Compile(options.Run)
+		if err != nil {
+			fmt.Fprintf(w, "invalid -run: %v\n", err)
+			return 2
+		}
+		named = Named(re)
+	}
+
+	tagged, err := Tagged(options.Tags)
+	if err != nil {
+		fmt.Fprintf(w, "invalid -tags: %v\n", err)
+		return 2
+	}
+
+	if options.Target != "" {
+		os.Setenv("BUILD_ENDPOINT", options.Target)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+
+	config := append([]assay.Config{http.Default(), assay.Logging(level)}, opts...)
+	results := s.execute(ctx, And(named, tagged), options.Parallel, capture(options.Format), config...)
+
+	if err = reporter(options.Format, w).Report(results); err != nil {
+		fmt.Fprintln(w, err)
+		return 2
+	}
+
+	for _, r := range results {
+		if r.Fail != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+func reporter(format string, w io.Writer) Reporter {
+	switch format {
+	case "", "text":
+		return Text(w)
+	case "json", "jsonl":
+		return JSONLines(w)
+	case "junit", "xml":
+		return JUnit(w)
+	case "tap":
+		return TAP(w)
+	case "html":
+		return HTML(w)
+	}
+	return nil
+}
+
+// capture returns the level of traffic details required by the report, only
+// JSON Lines and HTML reports render content of packets.
+func capture(format string) int {
+	switch format {
+	case "json", "jsonl", "html":
+		return assay.LogLevelDebug
+	}
+	return assay.LogLevelIngress
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func get(path string, code µ.StatusCode) func() assay.Arrow {
+	return func() assay.Arrow {
+		return µ.Join(
+			ø.GET("%s"+path, assay.Host("")),
+			ƒ.Code(code),
+		)
+	}
+}
+
+func mkSuite() *suite.Suite {
+	s := suite.New()
+	s.Register("Ok", get("/ok", µ.StatusOK), suite.Tag("smoke"), suite.Owner("a"))
+	s.Register("NotFound", get("/other", µ.StatusOK), suite.Tag("slow"))
+	return s
+}
+
+func TestSuiteMain(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+	defer os.Unsetenv("BUILD_ENDPOINT")
+
+	buf := &bytes.Buffer{}
+	code := mkSuite().Main([]string{"-target", ts.URL, "-parallel", "2"}, buf)
+
+	if code != 1 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "PASS  Ok") ||
+		!strings.Contains(out, "FAIL  NotFound") ||
+		!strings.Contains(out, "2 scenarios, 1 failed") {
+		t.Errorf("unexpected output %s", out)
+	}
+}
+
+func TestSuiteMainFilter(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+	defer os.Unsetenv("BUILD_ENDPOINT")
+
+	buf := &bytes.Buffer{}
+	code := mkSuite().Main([]string{"-target", ts.URL, "-run", "^Ok$", "-format", "json"}, buf)
+
+	if code != 0 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+
+	var r struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil || r.Name != "Ok" || r.Status != "pass" {
+		t.Errorf("unexpected output %s", buf.String())
+	}
+}
+
+func TestSuiteMainTags(t *testing.T) {
+	ts := mock()
+	defer ts.Close()
+	defer os.Unsetenv("BUILD_ENDPOINT")
+
+	buf := &bytes.Buffer{}
+	code := mkSuite().Main([]string{"-target", ts.URL, "-tags", "!slow", "-format", "json"}, buf)
+
+	if code != 0 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+
+	var r suite.Record
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil || r.Name != "Ok" || r.Owner != "a" || len(r.Tags) != 1 {
+		t.Errorf("unexpected output %s", buf.String())
+	}
+}
+
+func TestSuiteMainInvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-log", "verbose"},
+		{"-run", "("},
+		{"-tags", "smoke &&"},
+		{"-format", "yaml"},
+		{"-unknown"},
+	} {
+		if code := mkSuite().Main(args, &bytes.Buffer{}); code != 2 {
+			t.Errorf("unexpected exit code %d for %v", code, args)
+		}
+	}
+}
+
+//
+func mock() *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/ok":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+}
@@ -0,0 +1,419 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+/*
+
+Result of scenario execution, it holds the failure of scenario, steps of
+arrow composition and the I/O traffic captured by side effects.
+*/
+type Result struct {
+	Name        string
+	Tags        []string
+	Owner       string
+	Description string
+	Duration    time.Duration
+	Fail        error
+	Trace       assay.Trace
+	Traffic     []*assay.Event
+}
+
+/*
+
+exec evaluates the scenario with own I/O category, the traffic is captured
+by the observer of category up to the level of details and steps are traced.
+Content of packets is captured only at debug level.
+*/
+func (s *Suite) exec(scenario Scenario, level int, opts ...assay.Config) Result {
+	s.Lock()
+	hooks := s.hooks
+	s.Unlock()
+
+	arrow := scenario.Arrow()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		arrow = hooks[i](scenario, arrow)
+	}
+
+	var mu sync.Mutex
+	traffic := []*assay.Event{}
+	observer := assay.LoggerFunc(func(e *assay.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		traffic = append(traffic, e)
+	})
+
+	cat := assay.IO(append(append([]assay.Config{}, opts...), assay.ObserveAt(level, observer), assay.Tracing())...)
+
+	t := time.Now()
+	cat = arrow(cat)
+	cat.Reset()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return Result{
+		Name:        scenario.Name,
+		Tags:        scenario.Tags,
+		Owner:       scenario.Owner,
+		Description: scenario.Description,
+		Duration:    time.Since(t),
+		Fail:        cat.Fail,
+		Trace:       cat.Trace(),
+		Traffic:     traffic,
+	}
+}
+
+// Status of scenario: pass or fail
+func (r Result) Status() string {
+	if r.Fail != nil {
+		return "fail"
+	}
+	return "pass"
+}
+
+/*
+
+Type returns type of the failure, e.g. http.StatusCode or *assay.Mismatch.
+The failure of exhausted retries is reported by type of the last failure.
+*/
+func (r Result) Type() string {
+	if r.Fail == nil {
+		return ""
+	}
+
+	var exhausted *assay.Exhausted
+	if errors.As(r.Fail, &exhausted) {
+		return fmt.Sprintf("%T", exhausted.Err)
+	}
+	return fmt.Sprintf("%T", r.Fail)
+}
+
+/*
+
+Explain formats the failure of scenario for humans, the failure is prefixed
+with the failed step if it is known, e.g. step 5 recv.Code: HTTP 404
+*/
+func (r Result) Explain() string {
+	if r.Fail == nil {
+		return ""
+	}
+
+	if step, ok := r.Trace.Failed(); ok {
+		return fmt.Sprintf("step %d %s: %s", step.Index, step.Label, Explain(r.Fail))
+	}
+	return Explain(r.Fail)
+}
+
+// Diff returns the diff of mismatch failure
+func (r Result) Diff() string {
+	var mismatch *assay.Mismatch
+	if errors.As(r.Fail, &mismatch) {
+		return mismatch.Diff
+	}
+	return ""
+}
+
+/*
+
+Exchange summarizes the last request and response of scenario
+*/
+func (r Result) Exchange() string {
+	var egress, ingress *assay.Event
+	for _, e := range r.Traffic {
+		switch e.Type {
+		case assay.EventEgress:
+			egress, ingress = e, nil
+		case assay.EventIngress:
+			ingress = e
+		}
+	}
+
+	switch {
+	case egress == nil:
+		return ""
+	case ingress == nil:
+		return fmt.Sprintf("%s %s", egress.Method, egress.URL)
+	default:
+		return fmt.Sprintf("%s %s ⟼ %d %v", egress.Method, egress.URL, ingress.Status, ingress.Duration)
+	}
+}
+
+/*
+
+Reporter writes results of suite in machine or human readable format
+*/
+type Reporter interface {
+	Report([]Result) error
+}
+
+// ReporterFunc is an adapter to use ordinary functions as Reporter
+type ReporterFunc func([]Result) error
+
+// Report calls f(results)
+func (f ReporterFunc) Report(results []Result) error { return f(results) }
+
+/*
+
+Text reports results for humans
+*/
+func Text(w io.Writer) Reporter {
+	return ReporterFunc(func(results []Result) error {
+		failed := 0
+		for _, r := range results {
+			if r.Fail == nil {
+				fmt.Fprintf(w, "PASS  %s (%v)\n", r.Name, r.Duration)
+				continue
+			}
+
+			failed++
+			fmt.Fprintf(w, "FAIL  %s (%v)\n%s\n", r.Name, r.Duration, indent(r.Explain()))
+			if exchange := r.Exchange(); exchange != "" {
+				fmt.Fprintf(w, "    %s\n", exchange)
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "%d scenarios, %d failed\n", len(results), failed)
+		return err
+	})
+}
+
+/*
+
+Record is JSON representation of the result
+*/
+type Record struct {
+	Name        string    `json:"name"`
+	Tags        []string  `json:"tags,omitempty"`
+	Owner       string    `json:"owner,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	Duration    float64   `json:"duration"`
+	Error       string    `json:"error,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Diff        string    `json:"diff,omitempty"`
+	Exchange    string    `json:"exchange,omitempty"`
+	Steps       []Step    `json:"steps,omitempty"`
+	Traffic     []Message `json:"traffic,omitempty"`
+}
+
+/*
+
+Step is JSON representation of the step of arrow composition
+*/
+type Step struct {
+	Label    string  `json:"label"`
+	Index    int     `json:"index"`
+	Depth    int     `json:"depth"`
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+}
+
+/*
+
+Message is JSON representation of I/O traffic event
+*/
+type Message struct {
+	Type     string              `json:"type"`
+	Method   string              `json:"method"`
+	URL      string              `json:"url"`
+	Status   int                 `json:"status,omitempty"`
+	Duration float64             `json:"duration,omitempty"`
+	Size     int64               `json:"size"`
+	Header   map[string][]string `json:"header,omitempty"`
+	Body     string              `json:"body,omitempty"`
+}
+
+// Record builds JSON representation of the result
+func (r Result) Record() Record {
+	rec := Record{
+		Name:        r.Name,
+		Tags:        r.Tags,
+		Owner:       r.Owner,
+		Description: r.Description,
+		Status:      r.Status(),
+		Duration:    r.Duration.Seconds(),
+		Type:        r.Type(),
+		Diff:        r.Diff(),
+		Exchange:    r.Exchange(),
+	}
+
+	if r.Fail != nil {
+		rec.Error = r.Fail.Error()
+	}
+
+	for _, step := range r.Trace {
+		trace := Step{
+			Label:    step.Label,
+			Index:    step.Index,
+			Depth:    step.Depth,
+			Duration: step.Duration().Seconds(),
+		}
+		if step.Fail != nil {
+			trace.Error = step.Fail.Error()
+		}
+		rec.Steps = append(rec.Steps, trace)
+	}
+
+	for _, e := range r.Traffic {
+		msg := Message{
+			Type:     e.Type,
+			Method:   e.Method,
+			Status:   e.Status,
+			Duration: e.Duration.Seconds(),
+			Size:     e.Size,
+			Header:   e.Header,
+			Body:     string(e.Body),
+		}
+		if e.URL != nil {
+			msg.URL = e.URL.String()
+		}
+		rec.Traffic = append(rec.Traffic, msg)
+	}
+
+	return rec
+}
+
+/*
+
+JSONLines reports results as JSON Lines, one Record per scenario
+*/
+func JSONLines(w io.Writer) Reporter {
+	return ReporterFunc(func(results []Result) error {
+		enc := json.NewEncoder(w)
+		for _, r := range results {
+			if err := enc.Encode(r.Record()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// JUnit XML schema
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     float64     `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       float64         `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+/*
+
+JUnit reports results as JUnit XML
+*/
+func JUnit(w io.Writer) Reporter {
+	return ReporterFunc(func(results []Result) error {
+		report := junitSuite{Name: "assay", Tests: len(results)}
+
+		for _, r := range results {
+			report.Time += r.Duration.Seconds()
+			c := junitCase{
+				Name:      r.Name,
+				ClassName: "assay",
+				Time:      r.Duration.Seconds(),
+				SystemOut: r.Exchange(),
+			}
+
+			if len(r.Tags) > 0 {
+				c.Properties = append(c.Properties, junitProperty{"tags", strings.Join(r.Tags, ",")})
+			}
+			if r.Owner != "" {
+				c.Properties = append(c.Properties, junitProperty{"owner", r.Owner})
+			}
+
+			if r.Fail != nil {
+				report.Failures++
+				c.Failure = &junitFailure{
+					Message: strings.SplitN(r.Fail.Error(), "\n", 2)[0],
+					Type:    r.Type(),
+					Content: r.Explain(),
+				}
+			}
+			report.Cases = append(report.Cases, c)
+		}
+
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	})
+}
+
+/*
+
+TAP reports results as Test Anything Protocol stream, failures are
+described with YAML diagnostic blocks.
+*/
+func TAP(w io.Writer) Reporter {
+	return ReporterFunc(func(results []Result) error {
+		fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(results))
+
+		for i, r := range results {
+			if r.Fail == nil {
+				fmt.Fprintf(w, "ok %d - %s\n", i+1, r.Name)
+				continue
+			}
+
+			fmt.Fprintf(w, "not ok %d - %s\n", i+1, r.Name)
+			fmt.Fprintf(w, "  ---\n")
+			fmt.Fprintf(w, "  message: %q\n", strings.SplitN(r.Fail.Error(), "\n", 2)[0])
+			fmt.Fprintf(w, "  type: %q\n", r.Type())
+			fmt.Fprintf(w, "  duration_ms: %d\n", r.Duration.Milliseconds())
+			if exchange := r.Exchange(); exchange != "" {
+				fmt.Fprintf(w, "  exchange: %q\n", exchange)
+			}
+			if diff := r.Diff(); diff != "" {
+				fmt.Fprintf(w, "  diff: |\n%s\n", indent(diff))
+			}
+			fmt.Fprintf(w, "  ...\n")
+		}
+		return nil
+	})
+}
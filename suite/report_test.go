@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func results(t *testing.T) []suite.Result {
+	ts := mock()
+	t.Cleanup(ts.Close)
+
+	os.Setenv("BUILD_ENDPOINT", ts.URL)
+	t.Cleanup(func() { os.Unsetenv("BUILD_ENDPOINT") })
+
+	return mkSuite().Exec(context.Background(), nil, 1, µ.Default())
+}
+
+func TestResult(t *testing.T) {
+	seq := results(t)
+
+	if seq[0].Status() != "pass" || seq[1].Status() != "fail" {
+		t.Errorf("unexpected status %v", seq)
+	}
+
+	if !strings.HasPrefix(seq[1].Explain(), "step 2 recv.Code: ") {
+		t.Errorf("unexpected explanation %s", seq[1].Explain())
+	}
+
+	if seq[1].Type() != "http.StatusCode" {
+		t.Errorf("unexpected type %s", seq[1].Type())
+	}
+
+	if len(seq[1].Traffic) != 2 || !strings.HasPrefix(seq[1].Exchange(), "GET "+os.Getenv("BUILD_ENDPOINT")) {
+		t.Errorf("traffic is not captured %v", seq[1].Traffic)
+	}
+
+	diff := suite.Result{Fail: &assay.Exhausted{Attempts: 2, Err: &assay.Mismatch{Diff: "- a"}}}
+	if diff.Type() != "*assay.Mismatch" || diff.Diff() != "- a" {
+		t.Errorf("unexpected failure %s %s", diff.Type(), diff.Diff())
+	}
+
+	if (suite.Result{Fail: errors.New("fail")}).Exchange() != "" {
+		t.Error("unexpected exchange")
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := suite.JSONLines(buf).Report(results(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	seq := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(seq) != 2 {
+		t.Fatalf("unexpected output %s", buf.String())
+	}
+
+	var r suite.Record
+	if err := json.Unmarshal([]byte(seq[1]), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Name != "NotFound" || r.Status != "fail" || r.Type != "http.StatusCode" ||
+		len(r.Traffic) != 2 || r.Traffic[1].Status != 404 {
+		t.Errorf("unexpected record %+v", r)
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := suite.JUnit(buf).Report(results(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	var r struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Cases    []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Type string `xml:"type,attr"`
+			} `xml:"failure"`
+		} `xml:"testcase"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Tests != 2 || r.Failures != 1 || len(r.Cases) != 2 ||
+		r.Cases[0].Failure != nil ||
+		r.Cases[1].Failure == nil || r.Cases[1].Failure.Type != "http.StatusCode" {
+		t.Errorf("unexpected report %s", buf.String())
+	}
+}
+
+func TestTAP(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := suite.TAP(buf).Report([]suite.Result{
+		{Name: "A"},
+		{Name: "B", Fail: &assay.Mismatch{Diff: "- a: 1\n+ a: 2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "TAP version 13\n1..2\nok 1 - A\nnot ok 2 - B\n  ---\n") ||
+		!strings.Contains(out, "  type: \"*assay.Mismatch\"\n") ||
+		!strings.Contains(out, "  diff: |\n    - a: 1\n    + a: 2\n") ||
+		!strings.HasSuffix(out, "  ...\n") {
+		t.Errorf("unexpected output %s", out)
+	}
+}
@@ -0,0 +1,308 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package suite registers named Behavior as a Code scenarios and runs them
+within `go test`. Each scenario is executed as a subtest with own I/O
+category, so that `-run` flag filters scenarios by name.
+
+  func init() {
+    suite.Register("HealthCheck", HealthCheck)
+  }
+
+  func HealthCheck() assay.Arrow {
+    return http.Join(
+      ø.GET("https://example.com"),
+      ƒ.Code(http.StatusOK),
+    )
+  }
+
+  func TestSuite(t *testing.T) {
+    suite.Run(t, http.Default())
+  }
+
+*/
+package suite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/assay-it/sdk-go/assay"
+)
+
+/*
+
+Scenario is a named Behavior as a Code scenario. The arrow is a constructor
+of scenario, it is called for each run so that scenario closures do not
+share the state across runs. Tags, owner and description are metadata used
+to select scenarios and to report results.
+*/
+type Scenario struct {
+	Name        string
+	Arrow       func() assay.Arrow
+	Tags        []string
+	Owner       string
+	Description string
+}
+
+/*
+
+Option defines metadata of the scenario
+  suite.Register("CreateUser", CreateUser,
+    suite.Tag("smoke", "destructive"),
+    suite.Owner("team-identity"),
+  )
+*/
+type Option func(*Scenario)
+
+// Tag the scenario, e.g. smoke, destructive or slow
+func Tag(tags ...string) Option {
+	return func(s *Scenario) {
+		s.Tags = append(s.Tags, tags...)
+	}
+}
+
+// Owner of the scenario
+func Owner(owner string) Option {
+	return func(s *Scenario) {
+		s.Owner = owner
+	}
+}
+
+// Describe the scenario for humans
+func Describe(description string) Option {
+	return func(s *Scenario) {
+		s.Description = description
+	}
+}
+
+// HasTag checks if the scenario is tagged
+func (s Scenario) HasTag(tag string) bool {
+	for _, x := range s.Tags {
+		if x == tag {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+
+Suite is a registry of scenarios
+*/
+type Suite struct {
+	sync.Mutex
+	seq   []Scenario
+	hooks []Hook
+}
+
+/*
+
+Hook wraps the arrow of scenario before its execution, e.g. to trace
+scenarios or to prepare fixtures.
+*/
+type Hook func(Scenario, assay.Arrow) assay.Arrow
+
+// std is the default suite used by package level functions
+var std = &Suite{}
+
+/*
+
+New creates an empty suite
+*/
+func New() *Suite {
+	return &Suite{}
+}
+
+/*
+
+Register adds named scenario to the suite. It panics if the name is already
+registered.
+*/
+func (s *Suite) Register(name string, arrow func() assay.Arrow, opts ...Option) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, x := range s.seq {
+		if x.Name == name {
+			panic(fmt.Errorf("scenario %s is already registered", name))
+		}
+	}
+
+	scenario := Scenario{Name: name, Arrow: arrow}
+	for _, opt := range opts {
+		opt(&scenario)
+	}
+	s.seq = append(s.seq, scenario)
+}
+
+/*
+
+Use installs hooks to the suite, the first installed hook is the outermost
+one.
+*/
+func (s *Suite) Use(hooks ...Hook) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.hooks = append(s.hooks, hooks...)
+}
+
+/*
+
+Scenarios returns registered scenarios in the order of registration
+*/
+func (s *Suite) Scenarios() []Scenario {
+	s.Lock()
+	defer s.Unlock()
+
+	seq := make([]Scenario, len(s.seq))
+	copy(seq, s.seq)
+	return seq
+}
+
+/*
+
+Run executes each scenario of the suite as a subtest. The I/O category of
+scenario is created with given configs. The category is bound with the
+deadline of the test and logs I/O traffic into the test log. Results of
+executed scenarios are returned for reporting, the traffic of results omits
+content of packets.
+  results := suite.Run(t, http.Default())
+  suite.JUnit(w).Report(results)
+
+The environment variable ASSAY_TAGS defines the tag expression, scenarios
+not matching the expression are skipped.
+  ASSAY_TAGS="smoke && !slow" go test ./...
+*/
+func (s *Suite) Run(t *testing.T, opts ...assay.Config) []Result {
+	t.Helper()
+
+	tagged, err := Tagged(os.Getenv("ASSAY_TAGS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []Result{}
+	for _, scenario := range s.Scenarios() {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			t.Helper()
+
+			if !tagged(scenario) {
+				t.Skipf("tags %v do not match %s", scenario.Tags, os.Getenv("ASSAY_TAGS"))
+			}
+
+			ctx, cancel := deadline(t)
+			defer cancel()
+
+			result := s.exec(scenario, assay.LogLevelIngress, append(append([]assay.Config{}, opts...), assay.Context(ctx), testLogger(t))...)
+			if result.Fail != nil {
+				t.Error(result.Explain())
+			}
+			results = append(results, result)
+		})
+	}
+	return results
+}
+
+// testLogger logs I/O traffic into the test log if logging is enabled
+func testLogger(t *testing.T) assay.Config {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		if cat.LogLevel > assay.LogLevelNone {
+			return assay.LogWith(assay.TestLogger(t))(cat)
+		}
+		return cat
+	}
+}
+
+// grace is time reserved to report the failure before test deadline
+const grace = time.Second
+
+func deadline(t *testing.T) (context.Context, context.CancelFunc) {
+	if at, ok := t.Deadline(); ok {
+		return context.WithDeadline(context.Background(), at.Add(-grace))
+	}
+	return context.WithCancel(context.Background())
+}
+
+/*
+
+Register adds named scenario to the default suite.
+*/
+func Register(name string, arrow func() assay.Arrow, opts ...Option) {
+	std.Register(name, arrow, opts...)
+}
+
+/*
+
+Use installs hooks to the default suite.
+*/
+func Use(hooks ...Hook) {
+	std.Use(hooks...)
+}
+
+/*
+
+Scenarios returns scenarios of the default suite.
+*/
+func Scenarios() []Scenario {
+	return std.Scenarios()
+}
+
+/*
+
+Run executes scenarios of the default suite as subtests.
+*/
+func Run(t *testing.T, opts ...assay.Config) []Result {
+	t.Helper()
+	return std.Run(t, opts...)
+}
+
+/*
+
+Explain formats the failure of scenario for humans. The diff of mismatch is
+printed line by line, failures of parallel arrows are enumerated.
+*/
+func Explain(err error) string {
+	var compound *assay.Compound
+	if errors.As(err, &compound) {
+		seq := make([]string, len(compound.Errors))
+		for i, e := range compound.Errors {
+			seq[i] = fmt.Sprintf("(%d) %s", i+1, Explain(e))
+		}
+		return strings.Join(seq, "\n")
+	}
+
+	var mismatch *assay.Mismatch
+	if errors.As(err, &mismatch) {
+		head := strings.TrimRight(strings.Replace(err.Error(), mismatch.Diff, "", 1), ": ")
+		if head == "" {
+			return fmt.Sprintf("Mismatch:\n%s", indent(mismatch.Diff))
+		}
+		return fmt.Sprintf("%s: Mismatch:\n%s", head, indent(mismatch.Diff))
+	}
+
+	return err.Error()
+}
+
+func indent(s string) string {
+	seq := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range seq {
+		seq[i] = "    " + line
+	}
+	return strings.Join(seq, "\n")
+}
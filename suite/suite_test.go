@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func TestRun(t *testing.T) {
+	seq := []string{}
+	scenario := func(name string) func() assay.Arrow {
+		return func() assay.Arrow {
+			return func(cat *assay.IOCat) *assay.IOCat {
+				if _, ok := cat.Context().Deadline(); !ok {
+					t.Error("deadline is not propagated")
+				}
+				if cat.Verbosity() != assay.LogLevelIngress {
+					t.Error("content of packets is captured")
+				}
+				seq = append(seq, name)
+				return cat
+			}
+		}
+	}
+
+	s := suite.New()
+	s.Register("A", scenario("a"))
+	s.Register("B", scenario("b"))
+	s.Run(t)
+
+	if len(seq) != 2 || seq[0] != "a" || seq[1] != "b" {
+		t.Errorf("unexpected scenarios %v", seq)
+	}
+}
+
+func TestRunTags(t *testing.T) {
+	seq := []string{}
+	scenario := func(name string) func() assay.Arrow {
+		return func() assay.Arrow {
+			return func(cat *assay.IOCat) *assay.IOCat {
+				seq = append(seq, name)
+				return cat
+			}
+		}
+	}
+
+	os.Setenv("ASSAY_TAGS", "smoke")
+	defer os.Unsetenv("ASSAY_TAGS")
+
+	s := suite.New()
+	s.Register("A", scenario("a"), suite.Tag("smoke"), suite.Describe("scenario a"))
+	s.Register("B", scenario("b"), suite.Tag("slow"))
+	results := s.Run(t)
+
+	if len(seq) != 1 || seq[0] != "a" || len(results) != 1 || results[0].Description != "scenario a" {
+		t.Errorf("unexpected scenarios %v", seq)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate scenario is registered")
+		}
+	}()
+
+	s := suite.New()
+	s.Register("A", nil)
+	s.Register("A", nil)
+}
+
+func TestExplain(t *testing.T) {
+	diff := "- a: 1\n+ a: 2"
+	for err, expect := range map[error]string{
+		errors.New("fail"):          "fail",
+		&assay.Mismatch{Diff: diff}: "Mismatch:\n    - a: 1\n    + a: 2",
+		&assay.Exhausted{Attempts: 3, Err: &assay.Mismatch{Diff: diff}}:    "Exhausted after 3 attempts: Mismatch:\n    - a: 1\n    + a: 2",
+		&assay.Compound{Errors: []error{errors.New("a"), errors.New("b")}}: "(1) a\n(2) b",
+	} {
+		if msg := suite.Explain(err); msg != expect {
+			t.Errorf("unexpected explanation %q", msg)
+		}
+	}
+}
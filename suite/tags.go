@@ -0,0 +1,186 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+/*
+
+Selector decides if the scenario is executed by the runner
+*/
+type Selector func(Scenario) bool
+
+/*
+
+Named selects scenarios which names match the regular expression
+*/
+func Named(re *regexp.Regexp) Selector {
+	return func(s Scenario) bool { return re.MatchString(s.Name) }
+}
+
+/*
+
+And selects scenarios matching all selectors, nil selectors are ignored
+*/
+func And(seq ...Selector) Selector {
+	return func(s Scenario) bool {
+		for _, f := range seq {
+			if f != nil && !f(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+/*
+
+Tagged parses the tag expression into selector. The expression combines
+tags with operators ! (not), && (and), || (or) and parenthesis.
+  suite.Tagged("smoke && !(destructive || slow)")
+An empty expression selects all scenarios.
+*/
+func Tagged(expr string) (Selector, error) {
+	if strings.TrimSpace(expr) == "" {
+		return func(Scenario) bool { return true }, nil
+	}
+
+	p := &tagParser{expr: expr}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if tkn := p.next(); tkn != "" {
+		return nil, fmt.Errorf("invalid tag expression %q: unexpected %s", expr, tkn)
+	}
+	return f, nil
+}
+
+// tagParser is recursive descent parser of tag expressions
+//   or   := and { "||" and }
+//   and  := not { "&&" not }
+//   not  := "!" not | "(" or ")" | tag
+type tagParser struct {
+	expr string
+	pos  int
+	peek string
+}
+
+func (p *tagParser) or() (Selector, error) {
+	a, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.lookup() == "||" {
+		p.next()
+		b, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		a = func(a, b Selector) Selector {
+			return func(s Scenario) bool { return a(s) || b(s) }
+		}(a, b)
+	}
+	return a, nil
+}
+
+func (p *tagParser) and() (Selector, error) {
+	a, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.lookup() == "&&" {
+		p.next()
+		b, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		a = func(a, b Selector) Selector {
+			return func(s Scenario) bool { return a(s) && b(s) }
+		}(a, b)
+	}
+	return a, nil
+}
+
+func (p *tagParser) not() (Selector, error) {
+	switch tkn := p.next(); tkn {
+	case "!":
+		f, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(s Scenario) bool { return !f(s) }, nil
+	case "(":
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("invalid tag expression %q: missing )", p.expr)
+		}
+		return f, nil
+	case "", ")", "&&", "||":
+		if tkn == "" {
+			tkn = "end of expression"
+		}
+		return nil, fmt.Errorf("invalid tag expression %q: unexpected %s", p.expr, tkn)
+	default:
+		if strings.ContainsAny(tkn, "&|") {
+			return nil, fmt.Errorf("invalid tag expression %q: unexpected %s", p.expr, tkn)
+		}
+		return func(s Scenario) bool { return s.HasTag(tkn) }, nil
+	}
+}
+
+func (p *tagParser) lookup() string {
+	if p.peek == "" {
+		p.peek = p.scan()
+	}
+	return p.peek
+}
+
+func (p *tagParser) next() string {
+	tkn := p.lookup()
+	p.peek = ""
+	return tkn
+}
+
+func (p *tagParser) scan() string {
+	for p.pos < len(p.expr) && unicode.IsSpace(rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	if p.pos == len(p.expr) {
+		return ""
+	}
+
+	for _, op := range []string{"&&", "||", "!", "(", ")"} {
+		if strings.HasPrefix(p.expr[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) && !strings.ContainsRune(" \t\n&|!()", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		// single & or | is not an operator
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
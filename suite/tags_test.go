@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package suite_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/assay-it/sdk-go/suite"
+)
+
+func TestTagged(t *testing.T) {
+	smoke := suite.Scenario{Name: "A", Tags: []string{"smoke"}}
+	slow := suite.Scenario{Name: "B", Tags: []string{"smoke", "slow"}}
+	destructive := suite.Scenario{Name: "C", Tags: []string{"destructive"}}
+	none := suite.Scenario{Name: "D"}
+
+	for expr, expect := range map[string][]bool{
+		"":                               {true, true, true, true},
+		"smoke":                          {true, true, false, false},
+		"!smoke":                         {false, false, true, true},
+		"smoke && !slow":                 {true, false, false, false},
+		"slow || destructive":            {false, true, true, false},
+		"!(smoke || destructive)":        {false, false, false, true},
+		"smoke && slow || destructive":   {false, true, true, false},
+		"smoke && (slow || destructive)": {false, true, false, false},
+		"!!smoke":                        {true, true, false, false},
+	} {
+		f, err := suite.Tagged(expr)
+		if err != nil {
+			t.Errorf("unable to parse %q: %v", expr, err)
+			continue
+		}
+
+		for i, s := range []suite.Scenario{smoke, slow, destructive, none} {
+			if f(s) != expect[i] {
+				t.Errorf("%q do not select %s as expected", expr, s.Name)
+			}
+		}
+	}
+}
+
+func TestTaggedInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"smoke &&",
+		"&& smoke",
+		"smoke & slow",
+		"(smoke",
+		"smoke)",
+		"smoke slow",
+		"!",
+	} {
+		if _, err := suite.Tagged(expr); err == nil {
+			t.Errorf("invalid expression %q is accepted", expr)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	tagged, _ := suite.Tagged("smoke")
+	f := suite.And(suite.Named(regexp.MustCompile("^A")), tagged, nil)
+
+	for s, expect := range map[*suite.Scenario]bool{
+		{Name: "AB", Tags: []string{"smoke"}}: true,
+		{Name: "AB"}:                          false,
+		{Name: "BA", Tags: []string{"smoke"}}: false,
+	} {
+		if f(*s) != expect {
+			t.Errorf("unexpected selection of %v", s)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+/*
+
+Package telemetry traces execution of Behavior as a Code suites with
+OpenTelemetry. It creates a span per scenario and per HTTP exchange, the
+W3C traceparent header is injected into each request so that a failing
+check is linked with the server-side trace. It is the own module, kept apart
+from the core SDK, the package depends on OpenTelemetry SDK.
+
+  go get github.com/assay-it/sdk-go/telemetry
+
+  tp, err := telemetry.Provider(os.Stdout)
+  defer tp.Shutdown(context.Background())
+
+  tracer := tp.Tracer("assay")
+  suite.Use(telemetry.Hook(tracer))
+  suite.Main(telemetry.HTTP(tracer))
+
+Provider exports spans as JSON lines of stdouttrace exporter. Use Exporter
+to ship spans with any other exporter of OpenTelemetry SDK, e.g. OTLP over
+HTTP to the collector, which links them with server-side traces
+
+  exporter, err := otlptracehttp.New(ctx,
+    otlptracehttp.WithEndpoint("localhost:4318"),
+    otlptracehttp.WithInsecure(),
+  )
+  tp := telemetry.Exporter(exporter)
+  defer tp.Shutdown(context.Background())
+
+*/
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/assay-it/sdk-go/assay"
+	"github.com/assay-it/sdk-go/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+/*
+
+HTTP creates the span for each HTTP exchange evaluated by the category. The
+span is the child of scenario span if any. The traceparent header is injected
+into the request, the span records status code and failure of exchange.
+*/
+func HTTP(tracer trace.Tracer) assay.Config {
+	return assay.Intercept(func(f assay.Arrow) assay.Arrow {
+		return func(cat *assay.IOCat) *assay.IOCat {
+			if cat.HTTP == nil || cat.HTTP.Send == nil {
+				return f(cat)
+			}
+
+			send := cat.HTTP.Send
+			ctx, span := tracer.Start(cat.Context(), "HTTP "+send.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("http.request.method", send.Method),
+					attribute.String("url.full", send.URL.String()),
+				),
+			)
+			defer span.End()
+
+			if send.Header == nil {
+				send.Header = map[string]*string{}
+			}
+			propagation.TraceContext{}.Inject(ctx, header(send.Header))
+
+			cat = f(cat)
+
+			if cat.HTTP != nil && cat.HTTP.Recv != nil {
+				code := cat.HTTP.Recv.Code
+				span.SetAttributes(attribute.Int("http.response.status_code", code))
+				if code >= 400 {
+					span.SetStatus(codes.Error, http.StatusText(code))
+				}
+			}
+
+			if cat.Fail != nil {
+				span.RecordError(cat.Fail)
+				span.SetStatus(codes.Error, cat.Fail.Error())
+			}
+
+			return cat
+		}
+	})
+}
+
+/*
+
+Scenario creates the span for evaluation of the arrow, HTTP exchanges of
+the scenario are children of the span. The span records the failure of
+scenario and the failed step if tracing of steps is enabled.
+*/
+func Scenario(tracer trace.Tracer, name string, f assay.Arrow, attrs ...attribute.KeyValue) assay.Arrow {
+	return func(cat *assay.IOCat) *assay.IOCat {
+		parent := cat.Context()
+		ctx, span := tracer.Start(parent, name, trace.WithAttributes(attrs...))
+		defer span.End()
+
+		cat = f(assay.Context(ctx)(cat))
+		cat = assay.Context(parent)(cat)
+
+		if cat.Fail != nil {
+			if step, ok := cat.Trace().Failed(); ok {
+				span.SetAttributes(
+					attribute.String("assay.step", step.Label),
+					attribute.Int("assay.step.index", step.Index),
+				)
+			}
+			span.RecordError(cat.Fail)
+			span.SetStatus(codes.Error, cat.Fail.Error())
+		}
+
+		return cat
+	}
+}
+
+/*
+
+Hook creates the span for each scenario executed by the suite, tags and
+owner of scenario are recorded as span attributes.
+*/
+func Hook(tracer trace.Tracer) suite.Hook {
+	return func(s suite.Scenario, f assay.Arrow) assay.Arrow {
+		attrs := []attribute.KeyValue{attribute.String("assay.scenario", s.Name)}
+		if len(s.Tags) > 0 {
+			attrs = append(attrs, attribute.StringSlice("assay.tags", s.Tags))
+		}
+		if s.Owner != "" {
+			attrs = append(attrs, attribute.String("assay.owner", s.Owner))
+		}
+		return Scenario(tracer, s.Name, f, attrs...)
+	}
+}
+
+/*
+
+Provider creates the tracer provider, which exports spans as JSON to the
+writer (e.g. stdout or a local file). Spans are exported synchronously,
+shutdown the provider to flush them.
+*/
+func Provider(w io.Writer) (*sdktrace.TracerProvider, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create exporter: %w", err)
+	}
+
+	return Exporter(exporter), nil
+}
+
+/*
+
+Exporter creates the tracer provider, which exports spans with the exporter
+(e.g. OTLP exporter of OpenTelemetry SDK). Spans are exported synchronously,
+so that spans of failed suite are not lost when the runner exits. Shutdown
+the provider to flush them.
+*/
+func Exporter(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+}
+
+// header adapts headers of request to propagation.TextMapCarrier
+type header map[string]*string
+
+func (h header) Get(key string) string {
+	if val, ok := h[strings.ToLower(key)]; ok && val != nil {
+		return *val
+	}
+	return ""
+}
+
+func (h header) Set(key, value string) {
+	h[strings.ToLower(key)] = &value
+}
+
+func (h header) Keys() []string {
+	seq := make([]string, 0, len(h))
+	for key := range h {
+		seq = append(seq, key)
+	}
+	return seq
+}
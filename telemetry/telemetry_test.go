@@ -0,0 +1,132 @@
+//
+// Copyright (C) 2018 - 2021 assay.it
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/assay-it/sdk-go
+//
+
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/assay-it/sdk-go/assay"
+	µ "github.com/assay-it/sdk-go/http"
+	ƒ "github.com/assay-it/sdk-go/http/recv"
+	ø "github.com/assay-it/sdk-go/http/send"
+	"github.com/assay-it/sdk-go/suite"
+	"github.com/assay-it/sdk-go/telemetry"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHTTP(t *testing.T) {
+	ts, traceparent := mock()
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	f := telemetry.Scenario(tracer, "scenario",
+		µ.Join(
+			ø.GET(ts.URL+"/other"),
+			ƒ.Code(µ.StatusOK),
+		),
+	)
+
+	cat := f(assay.IO(µ.Default(), telemetry.HTTP(tracer), assay.Tracing()))
+	if cat.Fail == nil {
+		t.Fatal("scenario is not failed")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+
+	exchange, scenario := spans[0], spans[1]
+	if exchange.Name() != "HTTP GET" || exchange.Parent().SpanID() != scenario.SpanContext().SpanID() ||
+		exchange.Status().Code != codes.Error {
+		t.Errorf("unexpected exchange span %+v", exchange)
+	}
+
+	if scenario.Name() != "scenario" || scenario.Status().Code != codes.Error {
+		t.Errorf("unexpected scenario span %+v", scenario)
+	}
+
+	expect := exchange.SpanContext().TraceID().String()
+	if !strings.Contains(*traceparent, expect) || !strings.Contains(*traceparent, exchange.SpanContext().SpanID().String()) {
+		t.Errorf("traceparent %s is not injected", *traceparent)
+	}
+}
+
+func TestHook(t *testing.T) {
+	ts, _ := mock()
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	s := suite.New()
+	s.Use(telemetry.Hook(tracer))
+	s.Register("Ok", func() assay.Arrow {
+		return µ.Join(ø.GET(ts.URL+"/ok"), ƒ.Code(µ.StatusOK))
+	}, suite.Tag("smoke"))
+	s.Exec(context.Background(), nil, 1, µ.Default(), telemetry.HTTP(tracer))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[1].Name() != "Ok" || spans[1].Status().Code == codes.Error {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tp, err := telemetry.Provider(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := tp.Tracer("test").Start(context.Background(), "scenario")
+	span.End()
+	tp.Shutdown(context.Background())
+
+	if !strings.Contains(buf.String(), `"Name":"scenario"`) {
+		t.Errorf("span is not exported %s", buf.String())
+	}
+}
+
+func TestExporter(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := telemetry.Exporter(exporter)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "scenario")
+	span.End()
+
+	if spans := exporter.GetSpans(); len(spans) != 1 || spans[0].Name != "scenario" {
+		t.Errorf("span is not exported %v", spans)
+	}
+}
+
+//
+func mock() (*httptest.Server, *string) {
+	traceparent := ""
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("Traceparent")
+			switch {
+			case r.URL.Path == "/ok":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	), &traceparent
+}